@@ -1,13 +1,24 @@
 package main
 
 import (
+	"app/src/database"
+	"app/src/features"
+	"app/src/scripts/backtest"
 	"app/src/scripts/disableCryptos"
 	"app/src/scripts/generateDataset"
 	"app/src/scripts/generateModels"
 	"app/src/scripts/getBinanceData"
 	"app/src/scripts/getDailyPrices"
 	"app/src/scripts/getFearIndex"
+	"app/src/scripts/importSentiment"
+	"app/src/scripts/ledgerReport"
+	"app/src/scripts/repairGaps"
+	"app/src/scripts/syncSymbols"
+	"app/src/scripts/traderBot"
+	"app/src/sentiment"
+	"app/src/strategy"
 	"app/src/ui"
+	"app/src/utils"
 	"flag"
 	"fmt"
 	"log"
@@ -28,19 +39,66 @@ func main() {
 	showHelp := flag.Bool("h", false, "Exibe o menu de ajuda")
 	fearCMC := flag.Bool("GetFearCoinmarketcap", false, "Executa GetFearCoinmarketcap")
 	fearAltMe := flag.Bool("GetFearAlternativeMe", false, "Executa GetFearAlternativeMe")
+	getSentiment := flag.Bool("GetSentiment", false, "Importa os índices de sentimento registrados (todo o histórico ou -start/-end)")
+	sentimentSources := flag.String("sources", "", "Fontes de sentimento do GetSentiment separadas por vírgula (padrão: todas)")
+	importSentimentFlag := flag.Bool("ImportSentiment", false, "Importa um arquivo .csv ou .json de sentimento por crypto (use -file)")
+	sentimentFile := flag.String("file", "", "Arquivo do ImportSentiment")
+	sentimentSource := flag.String("sentimentSource", sentiment.LocalSource, "Fonte do sentimento por crypto gravada pelo ImportSentiment e usada no GenerateDataset (vazio desativa)")
+	sentimentFallback := flag.String("sentimentFallback", "api.alternative.me", "Índice de mercado usado no <COIN>_sentiment das cryptos sem valor próprio (vazio usa NaN)")
 	getBinance := flag.Bool("GetBinanceCurrentDayCryptos", false, "Executa GetBinanceCurrentDayCryptos")
+	priceCSV := flag.Bool("csv", false, "Também exporta o GetBinanceCurrentDayCryptos em DATA_DIR/last_history")
 	downloadBinance := flag.Bool("DownloadBinanceCryptoData", false, "Executa DownloadBinanceCryptoData")
 	getAllCryptos := flag.Bool("GetAllCryptos", false, "Busca todas as criptomoedas")
+	verifyExisting := flag.Bool("verifyExisting", false, "Baixa novamente no DownloadBinanceCryptoData todos os CSVs anteriores ao manifesto, mesmo os completos")
 	disableCryptosFlag := flag.Bool("DisableCryptos", false, "Executa DisableCryptos")
+	repairGapsFlag := flag.Bool("RepairGaps", false, "Lista e preenche os klines ausentes em DATA_DIR (necessita -start e -end)")
+	ledgerReportFlag := flag.Bool("LedgerReport", false, "Exibe o ledger de downloads do data.binance.vision")
 	resetCurrentDataset := flag.Bool("ResetCurrentDataset", false, "Subistitui o dataset atual")
 	generateDatasetFlag := flag.Bool("GenerateDataset", false, "Executa GenerateDataset")
 	generateModelsFlag := flag.Bool("GenerateModels", false, "Executa GenerateModels")
+	traderBotFlag := flag.Bool("TraderBot", false, "Executa TraderBot")
+	paper := flag.Bool("paper", false, "Executa o TraderBot em modo paper trading (ordens simuladas)")
+	paperBalance := flag.Float64("paperBalance", 1000, "Saldo inicial do modo paper trading (no ativo de cotação dos -symbols) e do Backtest (em USDT)")
+	migrateFlag := flag.Bool("Migrate", false, "Aplica as migrações pendentes do banco de dados")
+	migrateStatusFlag := flag.Bool("MigrateStatus", false, "Lista as migrações do banco de dados")
+	fearLegacyTZ := flag.String("fearLegacyTZ", "", "Fuso (ex: America/Sao_Paulo) usado pelo Migrate para converter para UTC as datas do fear_index gravadas por versões anteriores")
+	syncSymbolsFlag := flag.Bool("SyncSymbols", false, "Sincroniza criptomoedas com o exchangeInfo da Binance")
+	backtestFlag := flag.Bool("Backtest", false, "Executa Backtest (necessita -start e -end)")
+	fee := flag.Float64("fee", 0.001, "Taxa por trade do Backtest (0.001 = 0,1%)")
+	slippage := flag.Float64("slippage", 0, "Slippage por trade do Backtest (0.0005 = 0,05%)")
+	strategyName := flag.String("strategy", "percentChange", "Estratégia usada pelo TraderBot")
+	strategyParams := flag.String("strategyParams", "", "Parâmetros da estratégia (chave=valor,chave2=valor2)")
+	symbols := flag.String("symbols", "BTCUSDT", "Pares de trading separados por vírgula")
 	isSearchForAllFlg := flag.Bool("All", false, "Busca todos")
-	start := flag.String("start", "", "Data inicial (YYYY-MM-DD) para DisableCryptos")
-	end := flag.String("end", "", "Data final (YYYY-MM-DD) para DisableCryptos")
+	gapPolicy := flag.String("gapPolicy", "ffill", "Política para candles ausentes no GenerateDataset (ffill, drop, nan)")
+	percentReference := flag.String("percentReference", "close", "Preço de referência dos alvos do dataset_percent.csv (close, open)")
+	percentHorizon := flag.Int("percentHorizon", 1, "Quantidade de candles futuros usados nos alvos do dataset_percent.csv")
+	format := flag.String("format", "csv", "Formato dos arquivos do GenerateDataset (csv, parquet)")
+	compression := flag.String("compression", "snappy", "Compressão do formato parquet (snappy, zstd)")
+	labelHorizon := flag.Int("labelHorizon", 0, "Candles futuros dos rótulos do GenerateDataset (0 desativa)")
+	labelUpper := flag.Float64("labelUpper", 1, "Barreira superior (%) do rótulo triple-barrier")
+	labelLower := flag.Float64("labelLower", 1, "Barreira inferior (%) do rótulo triple-barrier")
+	featureSpec := flag.String("features", "", "Indicadores técnicos do GenerateDataset (ex: sma:20,ema:50,rsi:14,macd:12:26:9)")
+	fearInterpolate := flag.Bool("fearInterpolate", false, "Interpola os índices de medo entre os pontos diários no GenerateDataset")
+	fearMaxStaleness := flag.Duration("fearMaxStaleness", 48*time.Hour, "Idade máxima do índice de medo usado em uma linha do GenerateDataset (0 desativa)")
+	fearMissing := flag.String("fearMissing", "skip", "Linhas sem índice de medo no GenerateDataset (skip, nan)")
+	splitRatios := flag.String("splitRatios", "", "Proporções treino,validação,teste do GenerateDataset (ex: 0.7,0.15,0.15)")
+	splitDates := flag.String("splitDates", "", "Datas de início da validação e do teste do GenerateDataset (ex: 2024-06-01,2024-09-01)")
+	embargo := flag.Int("embargo", 0, "Candles descartados antes de cada fronteira da divisão do GenerateDataset")
+	folds := flag.Int("folds", 0, "Quantidade de folds de walk-forward do GenerateDataset")
+	workers := flag.Int("workers", 0, "Dias gerados em paralelo no GenerateDataset (0 usa a quantidade de CPUs)")
+	memoryMB := flag.Int("memoryMB", 1024, "Limite de memória (MB) do GenerateDataset, reduz os workers quando necessário")
+	interval := flag.String("interval", "1m", "Intervalo dos klines ("+strings.Join(utils.KlineIntervals, ", ")+")")
+	start := flag.String("start", "", "Data inicial (YYYY-MM-DD) para DisableCryptos e RepairGaps")
+	end := flag.String("end", "", "Data final (YYYY-MM-DD) para DisableCryptos e RepairGaps")
 
 	flag.Parse()
 
+	if !utils.IsValidInterval(*interval) {
+		fmt.Printf("❌ Intervalo inválido: %s. Use um de: %s\n", *interval, strings.Join(utils.KlineIntervals, ", "))
+		return
+	}
+
 	// Mostra ajuda se solicitado ou se nenhuma flag principal for passada
 	if len(os.Args) == 1 {
 		ui.MainCMD()
@@ -55,27 +113,68 @@ func main() {
 		executouAlgum = true
 	}
 
-	if *fearCMC {
-		fmt.Println("🔍 Executando GetFearCoinmarketcap...")
-		getFearIndex.GetFearCoinmarketcap(*isSearchForAllFlg)
+	if *migrateFlag {
+		fmt.Println("🗄️ Executando Migrate...")
+		runMigrate(*fearLegacyTZ)
+		executouAlgum = true
+	}
+
+	if *migrateStatusFlag {
+		runMigrateStatus()
+		executouAlgum = true
+	}
+
+	if *syncSymbolsFlag {
+		fmt.Println("🔍 Executando SyncSymbols...")
+		syncSymbols.Main()
+		executouAlgum = true
+	}
+
+	if *fearCMC || *fearAltMe || *getSentiment {
+		// Sem -start as fontes são sincronizadas a partir do último ponto gravado
+		from, to, err := parseSentimentRange(*start, *end)
+		if err != nil {
+			fmt.Println("❌", err)
+			return
+		}
+
+		if *fearCMC {
+			fmt.Println("🔍 Executando GetFearCoinmarketcap...")
+			getFearIndex.Main([]string{"CoinMarketCap"}, from, to, *isSearchForAllFlg)
+		}
+		if *fearAltMe {
+			fmt.Println("🔍 Executando GetFearAlternativeMe...")
+			getFearIndex.Main([]string{"api.alternative.me"}, from, to, *isSearchForAllFlg)
+		}
+		if *getSentiment {
+			fmt.Println("🔍 Executando GetSentiment...")
+			var names []string
+			if *sentimentSources != "" {
+				names = strings.Split(*sentimentSources, ",")
+			}
+			getFearIndex.Main(names, from, to, *isSearchForAllFlg)
+		}
 		executouAlgum = true
 	}
 
-	if *fearAltMe {
-		fmt.Println("🔍 Executando GetFearAlternativeMe...")
-		getFearIndex.GetFearAlternativeMe()
+	if *importSentimentFlag {
+		fmt.Println("🔍 Executando ImportSentiment...")
+		importSentiment.Main(*sentimentFile, *sentimentSource)
 		executouAlgum = true
 	}
 
 	if *getBinance {
 		fmt.Println("🔍 Executando GetBinanceCurrentDayCryptos...")
-		getDailyPrices.Main()
+		config := getDailyPrices.DefaultConfig()
+		config.Interval = *interval
+		config.CSV = *priceCSV
+		getDailyPrices.Main(config)
 		executouAlgum = true
 	}
 
 	if *downloadBinance {
 		fmt.Println("🔍 Executando DownloadBinanceCryptoData...")
-		getBinanceData.Main(*getAllCryptos)
+		getBinanceData.Main(*getAllCryptos, *interval, *verifyExisting)
 		executouAlgum = true
 	}
 
@@ -91,7 +190,33 @@ func main() {
 		}
 
 		fmt.Printf("🔄 Executando DisableCryptos de %s até %s...\n", *start, *end)
-		disableCryptos.Main(*start, *end)
+		disableCryptos.Main(*start, *end, *interval)
+		executouAlgum = true
+	}
+
+	if *repairGapsFlag {
+		if *start == "" || *end == "" {
+			fmt.Println("❌ Para usar -RepairGaps, forneça -start e -end no formato YYYY-MM-DD.")
+			return
+		}
+
+		if !isValidDate(*start) || !isValidDate(*end) || !isDateAfterOrEqual(*end, *start) {
+			fmt.Println("❌ Datas inválidas. Use o formato YYYY-MM-DD e certifique-se de que a data final seja igual ou posterior à inicial.")
+			return
+		}
+
+		fmt.Printf("🩹 Executando RepairGaps de %s até %s...\n", *start, *end)
+		config := repairGaps.DefaultConfig()
+		config.Start, _ = time.Parse("2006-01-02", *start)
+		config.End, _ = time.Parse("2006-01-02", *end)
+		config.Interval = *interval
+		repairGaps.Main(config)
+		executouAlgum = true
+	}
+
+	if *ledgerReportFlag {
+		fmt.Println("📒 Executando LedgerReport...")
+		ledgerReport.Main()
 		executouAlgum = true
 	}
 
@@ -111,7 +236,32 @@ func main() {
 			fmt.Println("❌ Erro ao converter data final:", err)
 			return
 		}
-		generateDataset.Main(startDate, endDate, *resetCurrentDataset)
+		config := generateDataset.DefaultConfig()
+		config.Start = startDate
+		config.End = endDate
+		config.ClearFiles = *resetCurrentDataset
+		config.Interval = *interval
+		config.GapPolicy = *gapPolicy
+		config.PercentReference = *percentReference
+		config.PercentHorizon = *percentHorizon
+		config.Features = *featureSpec
+		config.LabelHorizon = *labelHorizon
+		config.Format = *format
+		config.Compression = *compression
+		config.LabelUpper = *labelUpper
+		config.LabelLower = *labelLower
+		config.FearInterpolate = *fearInterpolate
+		config.FearMaxStaleness = *fearMaxStaleness
+		config.FearMissing = *fearMissing
+		config.Sentiment = *sentimentSource
+		config.SentimentFallback = *sentimentFallback
+		config.SplitRatios = *splitRatios
+		config.SplitDates = *splitDates
+		config.Embargo = *embargo
+		config.Folds = *folds
+		config.Workers = *workers
+		config.MemoryMB = *memoryMB
+		generateDataset.Main(config)
 		executouAlgum = true
 	}
 
@@ -120,6 +270,55 @@ func main() {
 		executouAlgum = true
 	}
 
+	if *traderBotFlag {
+		params, err := strategy.ParseParams(*strategyParams)
+		if err != nil {
+			fmt.Println("❌ Parâmetros de estratégia inválidos:", err)
+			return
+		}
+		config := traderBot.DefaultConfig()
+		config.Strategy = *strategyName
+		config.StrategyParams = params
+		config.Symbols = parseSymbols(*symbols)
+		config.Interval = *interval
+		config.Paper = *paper
+		config.PaperBalance = *paperBalance
+
+		fmt.Println("🤖 Executando TraderBot...")
+		traderBot.Main(config)
+		executouAlgum = true
+	}
+
+	if *backtestFlag {
+		if *start == "" || *end == "" {
+			fmt.Println("❌ Para usar -Backtest, forneça -start e -end no formato YYYY-MM-DD.")
+			return
+		}
+		if !isValidDate(*start) || !isValidDate(*end) || !isDateAfterOrEqual(*end, *start) {
+			fmt.Println("❌ Datas inválidas. Use o formato YYYY-MM-DD e certifique-se de que a data final seja igual ou posterior à inicial.")
+			return
+		}
+		params, err := strategy.ParseParams(*strategyParams)
+		if err != nil {
+			fmt.Println("❌ Parâmetros de estratégia inválidos:", err)
+			return
+		}
+		config := backtest.DefaultConfig()
+		config.Strategy = *strategyName
+		config.StrategyParams = params
+		config.Symbols = parseSymbols(*symbols)
+		config.Interval = *interval
+		config.Start, _ = time.Parse("2006-01-02", *start)
+		config.End, _ = time.Parse("2006-01-02", *end)
+		config.InitialBalance = *paperBalance
+		config.FeeRate = *fee
+		config.Slippage = *slippage
+
+		fmt.Println("🔍 Executando Backtest...")
+		backtest.Main(config)
+		executouAlgum = true
+	}
+
 	if !executouAlgum {
 		fmt.Println("❌ Nenhuma opção reconhecida. Use -h para ver os comandos disponíveis.")
 	}
@@ -133,18 +332,154 @@ func showUsage() {
 	fmt.Println()
 	fmt.Println("Opções:")
 	fmt.Println("  -h                            → Exibe este menu")
+	fmt.Println("  -Migrate                     → Cria/atualiza o esquema do banco de dados")
+	fmt.Println("  -MigrateStatus               → Lista as migrações aplicadas e pendentes")
+	fmt.Println("  -fearLegacyTZ                → Com -Migrate, converte para UTC as datas antigas do fear_index gravadas nesse fuso")
+	fmt.Println("  -SyncSymbols                 → Sincroniza criptomoedas com o exchangeInfo da Binance")
 	fmt.Println("  -GetFearCoinmarketcap        → Executa GetFearCoinmarketcap")
 	fmt.Println("  -GetFearAlternativeMe        → Executa GetFearAlternativeMe")
+	fmt.Println("  -GetSentiment                → Importa os índices de sentimento (use -sources e -start/-end)")
+	fmt.Println("  -ImportSentiment             → Importa sentimento por crypto de um arquivo .csv ou .json (necessita -file)")
 	fmt.Println("  -GetBinanceCurrentDayCryptos → Executa GetBinanceCurrentDayCryptos")
 	fmt.Println("  -DownloadBinanceCryptoData   → Executa DownloadBinanceCryptoData")
 	fmt.Println("  -DisableCryptos              → Executa DisableCryptos (necessita -start e -end)")
+	fmt.Println("  -RepairGaps                  → Lista e preenche os klines ausentes via API REST (necessita -start e -end)")
+	fmt.Println("  -LedgerReport                → Exibe os downloads registrados, 404 em cooldown e falhas por par")
 	fmt.Println("  -GenerateDataset             → Executa GenerateDataset")
+	fmt.Println("  -GenerateModels              → Executa GenerateModels")
+	fmt.Println("  -TraderBot                   → Executa TraderBot (use -paper para ordens simuladas)")
+	fmt.Println("  -Backtest                    → Executa Backtest (necessita -start e -end)")
+	fmt.Println()
+	fmt.Println("Flags opcionais:")
+	fmt.Println("  -interval                    → Intervalo dos klines (" + strings.Join(utils.KlineIntervals, ", ") + "), padrão 1m")
+	fmt.Println("  -csv                         → Também exporta o GetBinanceCurrentDayCryptos em DATA_DIR/last_history/<intervalo>")
+	fmt.Println("  -verifyExisting              → Baixa novamente todos os CSVs anteriores ao manifesto para conferir o checksum")
+	fmt.Println("  -gapPolicy                   → Candles ausentes no GenerateDataset: ffill, drop ou nan (padrão ffill)")
+	fmt.Println("  -percentReference            → Referência do dataset_percent.csv: close ou open (padrão close)")
+	fmt.Println("  -percentHorizon              → Candles futuros dos alvos do dataset_percent.csv (padrão 1)")
+	fmt.Println("  -features                    → Indicadores técnicos por crypto no GenerateDataset (ex: sma:20,rsi:14)")
+	fmt.Println("  -format                      → Formato do GenerateDataset: csv ou parquet (padrão csv)")
+	fmt.Println("  -compression                 → Compressão do parquet: snappy ou zstd (padrão snappy)")
+	fmt.Println("  -labelHorizon                → Rótulos do GenerateDataset sobre N candles futuros (padrão 0, desativado)")
+	fmt.Println("  -labelUpper / -labelLower    → Barreiras (%) do rótulo triple-barrier (padrão 1)")
+	fmt.Println("  -fearInterpolate             → Interpola os índices de medo entre os pontos diários (sem look-ahead)")
+	fmt.Println("  -fearMaxStaleness            → Idade máxima do índice de medo de uma linha (padrão 48h, 0 desativa)")
+	fmt.Println("  -fearMissing                 → Linhas sem índice de medo: skip ou nan (padrão skip)")
+	fmt.Println("  -sentimentSource             → Fonte do sentimento por crypto (<COIN>_sentiment), padrão local")
+	fmt.Println("  -sentimentFallback           → Índice de mercado das cryptos sem sentimento próprio (padrão api.alternative.me)")
+	fmt.Println("  -splitRatios / -splitDates   → Divide os datasets em treino/validação/teste (ex: 0.7,0.15,0.15)")
+	fmt.Println("  -embargo                     → Candles descartados antes de cada fronteira da divisão (padrão 0)")
+	fmt.Println("  -folds                       → Folds de walk-forward exportados em DATASET_DIR/splits (padrão 0)")
+	fmt.Println("  -workers                     → Dias gerados em paralelo no GenerateDataset (padrão: quantidade de CPUs)")
+	fmt.Println("  -memoryMB                    → Limite de memória do GenerateDataset em MB (padrão 1024)")
 	fmt.Println()
 	fmt.Println("Exemplo:")
 	fmt.Println("  main.exe -DisableCryptos -start 2024-01-01 -end 2024-12-31")
+	fmt.Println("  main.exe -GetSentiment -sources CoinMarketCap -start 2024-01-01 -end 2024-03-31")
+	fmt.Println("  main.exe -ImportSentiment -file scores.csv")
+	fmt.Println("  main.exe -DownloadBinanceCryptoData -interval 1h")
+	fmt.Println("  main.exe -RepairGaps -start 2024-01-01 -end 2024-12-31")
+	fmt.Println("  main.exe -LedgerReport")
+	fmt.Println("  main.exe -Migrate -fearLegacyTZ America/Sao_Paulo")
+	fmt.Println("  main.exe -GenerateDataset -start 2024-01-01 -end 2024-12-31 -interval 1h")
+	fmt.Println("  main.exe -TraderBot -paper -paperBalance 500")
+	fmt.Println("  main.exe -TraderBot -paper -strategy percentChange -strategyParams threshold=1,quantity=0.01 -symbols BTCUSDT,ETHUSDT")
+	fmt.Println("  main.exe -Backtest -strategy percentChange -symbols BTCUSDT -start 2024-01-01 -end 2024-03-31 -fee 0.001 -slippage 0.0005")
+	fmt.Println()
+	fmt.Println("Estratégias disponíveis:", strings.Join(strategy.Names(), ", "))
+	fmt.Println("Indicadores disponíveis:", strings.Join(features.Names(), ", "))
+	fmt.Println("Fontes de sentimento disponíveis:", strings.Join(sentiment.Names(), ", "))
 	fmt.Println(strings.Repeat("=", 40))
 }
 
+func runMigrate(fearLegacyTZ string) {
+	db, err := database.ConnectDatabase()
+	if err != nil {
+		log.Fatalf("Erro ao abrir o banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	applied, err := database.Migrate(db)
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	fmt.Printf("✅ %d migrações aplicadas\n", applied)
+
+	// Datas do fear_index gravadas no fuso local por versões anteriores
+	if fearLegacyTZ == "" {
+		if count, err := sentiment.LocalDates(db); err != nil {
+			fmt.Println("❌", err)
+		} else if count > 0 {
+			fmt.Printf("⚠️ %d datas do fear_index ainda no fuso local de versões anteriores. Converta com -Migrate -fearLegacyTZ <fuso>\n", count)
+		}
+		return
+	}
+	loc, err := time.LoadLocation(fearLegacyTZ)
+	if err != nil {
+		fmt.Println("❌ Fuso inválido:", err)
+		return
+	}
+	converted, removed, err := sentiment.ConvertLocalDates(db, loc)
+	if err != nil {
+		fmt.Println("❌ Erro ao converter as datas do fear_index:", err)
+		return
+	}
+	fmt.Printf("✅ Datas do fear_index convertidas de %s para UTC: %d convertidas, %d removidas (já existiam em UTC)\n", loc, converted, removed)
+}
+
+func runMigrateStatus() {
+	db, err := database.ConnectDatabase()
+	if err != nil {
+		log.Fatalf("Erro ao abrir o banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	migrations, err := database.MigrationStatus(db)
+	if err != nil {
+		fmt.Println("❌", err)
+		return
+	}
+	for _, migration := range migrations {
+		status := "⏳ pendente"
+		if migration.AppliedAt != nil {
+			status = "✅ aplicada em " + migration.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%04d_%s: %s\n", migration.Version, migration.Name, status)
+	}
+}
+
+func parseSymbols(raw string) []string {
+	var symbols []string
+	for _, symbol := range strings.Split(raw, ",") {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+// parseSentimentRange converte -start/-end das importações de sentimento.
+// A data final é inclusiva; datas vazias retornam zero.
+func parseSentimentRange(start, end string) (from, to time.Time, err error) {
+	if start != "" {
+		if from, err = time.Parse("2006-01-02", start); err != nil {
+			return from, to, fmt.Errorf("erro ao converter data inicial: %v", err)
+		}
+	}
+	if end != "" {
+		if to, err = time.Parse("2006-01-02", end); err != nil {
+			return from, to, fmt.Errorf("erro ao converter data final: %v", err)
+		}
+		to = to.Add(24*time.Hour - time.Second)
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return from, to, fmt.Errorf("data final deve ser igual ou posterior à data inicial")
+	}
+	return from, to, nil
+}
+
 func isValidDate(dateStr string) bool {
 	_, err := time.Parse("2006-01-02", dateStr)
 	return err == nil
@@ -0,0 +1,312 @@
+package binanceapi
+
+import (
+	"app/src/constants"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Prefixo dos cabeçalhos de peso usado por janela (ex: X-MBX-USED-WEIGHT-1M)
+const usedWeightHeader = "X-Mbx-Used-Weight-"
+
+// Config do cliente
+type Config struct {
+	// URL base das requisições (ex: https://api.binance.com ou um stub local)
+	BaseURL string
+	// Tempo máximo de cada requisição, incluindo a leitura do corpo
+	Timeout time.Duration
+	// Requisições simultâneas permitidas (0 não limita)
+	MaxConcurrent int
+	// Novas tentativas após erros de rede, 429, 418 e 5xx
+	MaxRetries int
+	// Espera da primeira nova tentativa, dobrada a cada falha até MaxDelay.
+	// Cada espera recebe uma variação aleatória (jitter) de até metade do valor.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Peso máximo por janela dos cabeçalhos X-MBX-USED-WEIGHT-<janela> (ex:
+	// "1M"). Ao atingir o valor as requisições esperam a próxima janela.
+	WeightLimits map[string]int
+}
+
+// DefaultConfig usa a API REST da Binance, que pode apontar para um servidor
+// local via BINANCE_API_BASE_URL. O limite da Binance é 6000 de peso por
+// minuto; a pausa começa antes para deixar folga a outros processos.
+func DefaultConfig() Config {
+	config := Config{
+		BaseURL:       constants.BINANCE_API_BASE_URL,
+		Timeout:       30 * time.Second,
+		MaxConcurrent: 10,
+		MaxRetries:    5,
+		BaseDelay:     time.Second,
+		MaxDelay:      time.Minute,
+		WeightLimits:  map[string]int{"1M": 5000},
+	}
+	if url := os.Getenv("BINANCE_API_BASE_URL"); url != "" {
+		config.BaseURL = strings.TrimRight(url, "/")
+	}
+	return config
+}
+
+// DataConfig usa os arquivos históricos do data.binance.vision, que podem
+// apontar para um servidor local via BINANCE_DATA_BASE_URL. Os arquivos
+// mensais são grandes, então o tempo máximo é maior.
+func DataConfig() Config {
+	config := DefaultConfig()
+	config.BaseURL = constants.BINANCE_DATA_BASE_URL
+	config.Timeout = 2 * time.Minute
+	config.WeightLimits = nil
+	if url := os.Getenv("BINANCE_DATA_BASE_URL"); url != "" {
+		config.BaseURL = strings.TrimRight(url, "/")
+	}
+	return config
+}
+
+// Client faz requisições GET à Binance respeitando os limites informados
+// pela API e pode ser compartilhado entre goroutines. Um 429/418 ou o peso
+// de uma janela no limite pausa todas as requisições do cliente.
+type Client struct {
+	config Config
+	http   *http.Client
+	slots  chan struct{}
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+func New(config Config) *Client {
+	client := &Client{
+		config: config,
+		http:   &http.Client{Timeout: config.Timeout},
+	}
+	if config.MaxConcurrent > 0 {
+		client.slots = make(chan struct{}, config.MaxConcurrent)
+	}
+	return client
+}
+
+// URL monta a URL completa de path (ex: /api/v3/klines) na URL base
+func (c *Client) URL(path string) string {
+	return c.config.BaseURL + path
+}
+
+// StatusError é retornado por Get quando a resposta não é 200
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("resposta inválida da Binance: %s %s", e.Status, strings.TrimSpace(e.Body))
+}
+
+// Get busca path na URL base com a query informada e retorna o corpo da
+// resposta. Respostas diferentes de 200 retornam *StatusError.
+func (c *Client) Get(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	rawURL := c.URL(path)
+	if len(query) > 0 {
+		rawURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler corpo da resposta: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: string(body)}
+	}
+	return body, nil
+}
+
+// Do envia uma requisição sem corpo (ex: GET de um arquivo), tentando
+// novamente após erros de rede, 429, 418 e 5xx. As demais respostas, inclusive
+// 404, são retornadas ao chamador, que deve fechar o corpo. Esgotadas as
+// tentativas retorna a última resposta ou o último erro.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if err := c.waitPause(ctx); err != nil {
+			return nil, err
+		}
+		resp, err := c.send(req)
+		if err != nil {
+			if ctx.Err() != nil || attempt == c.config.MaxRetries {
+				return nil, fmt.Errorf("erro ao fazer requisição para Binance: %w", err)
+			}
+			wait := c.backoff(attempt)
+			log.Printf("⚠️ Erro de rede em %s: %v. Nova tentativa em %s", req.URL.Path, err, wait.Round(time.Millisecond))
+			if err := sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		c.trackWeight(resp.Header)
+		if !retryable(resp.StatusCode) || attempt == c.config.MaxRetries {
+			return resp, nil
+		}
+		// Corpo descartado para reaproveitar a conexão
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		wait := c.backoff(attempt)
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot {
+			// Limite excedido (429) ou IP banido temporariamente (418): a
+			// Binance informa quanto esperar e a pausa vale para todos
+			if retryAfter, ok := parseRetryAfter(resp.Header); ok {
+				wait = retryAfter
+			}
+			c.pause(wait)
+		}
+		log.Printf("⏳ Binance respondeu %s para %s. Nova tentativa em %s", resp.Status, req.URL.Path, wait.Round(time.Millisecond))
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send faz a requisição ocupando uma das vagas de MaxConcurrent
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.slots != nil {
+		select {
+		case c.slots <- struct{}{}:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		defer func() { <-c.slots }()
+	}
+	return c.http.Do(req)
+}
+
+func retryable(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusTeapot || statusCode >= 500
+}
+
+// backoff é a espera exponencial da tentativa, com jitter
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.config.BaseDelay
+	for i := 0; i < attempt && delay < c.config.MaxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, c.config.MaxDelay)
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// Backoff espera a pausa atual do cliente e a espera exponencial da tentativa
+// attempt (0 na primeira nova tentativa). Serve a quem repete uma operação
+// além da requisição, como um download com corpo incompleto ou hash divergente.
+func (c *Client) Backoff(ctx context.Context, attempt int) error {
+	if err := c.waitPause(ctx); err != nil {
+		return err
+	}
+	return sleep(ctx, c.backoff(attempt))
+}
+
+// trackWeight lê os cabeçalhos X-MBX-USED-WEIGHT-<janela> e pausa até a
+// próxima janela quando o peso usado atinge o limite configurado
+func (c *Client) trackWeight(header http.Header) {
+	for key, values := range header {
+		if !strings.HasPrefix(key, usedWeightHeader) || len(values) == 0 {
+			continue
+		}
+		window := strings.ToUpper(strings.TrimPrefix(key, usedWeightHeader))
+		limit, ok := c.config.WeightLimits[window]
+		if !ok {
+			continue
+		}
+		used, err := strconv.Atoi(values[0])
+		if err != nil || used < limit {
+			continue
+		}
+		length, err := parseWindow(window)
+		if err != nil {
+			continue
+		}
+		wait := time.Until(time.Now().Truncate(length).Add(length))
+		if c.pause(wait) {
+			log.Printf("⏳ Peso usado na janela %s: %d de %d, aguardando %s", window, used, limit, wait.Round(time.Second))
+		}
+	}
+}
+
+// parseWindow converte a janela do cabeçalho (ex: 1M, 10S, 1H, 1D) em duração
+func parseWindow(window string) (time.Duration, error) {
+	if len(window) < 2 {
+		return 0, fmt.Errorf("janela inválida: %s", window)
+	}
+	count, err := strconv.Atoi(window[:len(window)-1])
+	if err != nil || count <= 0 {
+		return 0, fmt.Errorf("janela inválida: %s", window)
+	}
+	units := map[byte]time.Duration{'S': time.Second, 'M': time.Minute, 'H': time.Hour, 'D': 24 * time.Hour}
+	unit, ok := units[window[len(window)-1]]
+	if !ok {
+		return 0, fmt.Errorf("janela inválida: %s", window)
+	}
+	return time.Duration(count) * unit, nil
+}
+
+// parseRetryAfter lê o Retry-After em segundos
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// pause adia as próximas requisições por wait e indica se a pausa aumentou
+func (c *Client) pause(wait time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	until := time.Now().Add(wait)
+	if !until.After(c.pausedUntil) {
+		return false
+	}
+	c.pausedUntil = until
+	return true
+}
+
+// waitPause espera o fim da pausa atual do cliente
+func (c *Client) waitPause(ctx context.Context) error {
+	c.mu.Lock()
+	wait := time.Until(c.pausedUntil)
+	c.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	return sleep(ctx, wait)
+}
+
+func sleep(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
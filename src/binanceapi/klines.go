@@ -0,0 +1,104 @@
+package binanceapi
+
+import (
+	"app/src/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Klines por requisição aceitos pela API
+const MaxKlinesLimit = 1000
+
+// Klines busca até limit klines de symbol (ex: BTCUSDT) com OpenTime entre
+// startTime e endTime em milissegundos (0 não limita o respectivo lado)
+func (c *Client) Klines(ctx context.Context, symbol, interval string, startTime, endTime int64, limit int) ([]models.BinanceKline, error) {
+	query := url.Values{}
+	query.Set("symbol", symbol)
+	query.Set("interval", interval)
+	if startTime > 0 {
+		query.Set("startTime", strconv.FormatInt(startTime, 10))
+	}
+	if endTime > 0 {
+		query.Set("endTime", strconv.FormatInt(endTime, 10))
+	}
+	query.Set("limit", strconv.Itoa(limit))
+
+	body, err := c.Get(ctx, "/api/v3/klines", query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cada kline é uma lista [openTime, open, high, low, close, volume, closeTime, ...]
+	var rawKlines [][]json.RawMessage
+	if err := json.Unmarshal(body, &rawKlines); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar JSON: %w", err)
+	}
+
+	klines := make([]models.BinanceKline, 0, len(rawKlines))
+	for _, raw := range rawKlines {
+		if len(raw) < 12 {
+			continue
+		}
+		fields := make([]string, 12)
+		for i := range fields {
+			fields[i] = strings.Trim(string(raw[i]), `"`)
+		}
+		kline, err := parseKline(fields)
+		if err != nil {
+			return nil, fmt.Errorf("kline inválido: %w", err)
+		}
+		klines = append(klines, kline)
+	}
+	return klines, nil
+}
+
+// KlinesRange busca todos os klines com OpenTime em [startTime, endTime],
+// paginando de MaxKlinesLimit em MaxKlinesLimit
+func (c *Client) KlinesRange(ctx context.Context, symbol, interval string, startTime, endTime int64) ([]models.BinanceKline, error) {
+	var klines []models.BinanceKline
+	for startTime <= endTime {
+		page, err := c.Klines(ctx, symbol, interval, startTime, endTime, MaxKlinesLimit)
+		if err != nil {
+			return klines, err
+		}
+		klines = append(klines, page...)
+		if len(page) < MaxKlinesLimit {
+			break
+		}
+		startTime = page[len(page)-1].OpenTime + 1
+	}
+	return klines, nil
+}
+
+func parseKline(fields []string) (models.BinanceKline, error) {
+	openTime, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return models.BinanceKline{}, err
+	}
+	closeTime, err := strconv.ParseInt(fields[6], 10, 64)
+	if err != nil {
+		return models.BinanceKline{}, err
+	}
+	trades, err := strconv.Atoi(fields[8])
+	if err != nil {
+		return models.BinanceKline{}, err
+	}
+	return models.BinanceKline{
+		OpenTime:            openTime,
+		Open:                fields[1],
+		High:                fields[2],
+		Low:                 fields[3],
+		Close:               fields[4],
+		Volume:              fields[5],
+		CloseTime:           closeTime,
+		QuoteAssetVolume:    fields[7],
+		NumberOfTrades:      trades,
+		TakerBuyBaseVolume:  fields[9],
+		TakerBuyQuoteVolume: fields[10],
+		Ignore:              fields[11],
+	}, nil
+}
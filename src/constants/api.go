@@ -3,6 +3,10 @@ package constants
 const (
 	ALTERNATIVE_ME_API                = "https://api.alternative.me/fng"
 	COINMARKETCAP_FEAR_HISTORICAL_API = "https://pro-api.coinmarketcap.com/v3/fear-and-greed/historical"
-	BINANCE_API                       = "https://api.binance.com/api/v3/klines"
-	BINANCE_SYMBOLS_API               = "https://api.binance.com/api/v3/exchangeInfo"
+	BINANCE_API_BASE_URL              = "https://api.binance.com"
+	BINANCE_STREAM_BASE_URL           = "wss://stream.binance.com:9443"
+	BINANCE_DATA_BASE_URL             = "https://data.binance.vision"
+	BINANCE_EXCHANGE_INFO_PATH        = "/api/v3/exchangeInfo"
+	BINANCE_API                       = BINANCE_API_BASE_URL + "/api/v3/klines"
+	BINANCE_SYMBOLS_API               = BINANCE_API_BASE_URL + BINANCE_EXCHANGE_INFO_PATH
 )
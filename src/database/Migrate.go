@@ -0,0 +1,162 @@
+package database
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration é um arquivo NNNN_nome.sql da pasta migrations
+type Migration struct {
+	Version   int
+	Name      string
+	SQL       string
+	AppliedAt *time.Time
+}
+
+// Migrate aplica em ordem todas as migrações ainda não registradas em
+// schema_migrations, cada uma em sua própria transação. Retorna quantas foram aplicadas.
+func Migrate(db *sql.DB) (int, error) {
+	migrations, err := MigrationStatus(db)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, migration := range migrations {
+		if migration.AppliedAt != nil {
+			continue
+		}
+
+		if err := applyMigration(db, migration); err != nil {
+			return applied, fmt.Errorf("erro ao aplicar migração %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		log.Printf("🗄️ Migração aplicada: %04d_%s", migration.Version, migration.Name)
+		applied++
+	}
+
+	return applied, nil
+}
+
+// MigrationStatus lista todas as migrações embutidas, com a data de aplicação
+// das que já foram executadas neste banco.
+func MigrationStatus(db *sql.DB) ([]Migration, error) {
+	if err := createSchemaMigrationsTableIfNotExists(db); err != nil {
+		return nil, fmt.Errorf("erro ao garantir tabela schema_migrations: %w", err)
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	appliedAt := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var date time.Time
+		if err := rows.Scan(&version, &date); err != nil {
+			return nil, fmt.Errorf("erro ao ler linha: %w", err)
+		}
+		appliedAt[version] = date
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range migrations {
+		if t, ok := appliedAt[migrations[i].Version]; ok {
+			migrations[i].AppliedAt = &t
+		}
+	}
+
+	return migrations, nil
+}
+
+func applyMigration(db *sql.DB, migration Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(migration.SQL); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+		migration.Version, migration.Name, time.Now().UTC().Format("2006-01-02 15:04:05"),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func loadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar migrações: %w", err)
+	}
+
+	var migrations []Migration
+	seen := make(map[int]string)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		versionStr, name, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), "_")
+		if !ok {
+			return nil, fmt.Errorf("nome de migração inválido: %s (use NNNN_nome.sql)", entry.Name())
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("versão de migração inválida: %s", entry.Name())
+		}
+		if other, exists := seen[version]; exists {
+			return nil, fmt.Errorf("versão de migração duplicada: %s e %s", other, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler migração %s: %w", entry.Name(), err)
+		}
+
+		migrations = append(migrations, Migration{Version: version, Name: name, SQL: string(content)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func createSchemaMigrationsTableIfNotExists(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at DATETIME NOT NULL
+	);`
+	_, err := db.Exec(query)
+	return err
+}
@@ -0,0 +1,29 @@
+-- Esquema base usado por todos os scripts
+CREATE TABLE IF NOT EXISTS exchanges (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS cryptos (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	symbol TEXT NOT NULL UNIQUE,
+	name TEXT,
+	is_enabled INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS exchanges_cryptos (
+	exchange_id INTEGER NOT NULL REFERENCES exchanges(id),
+	crypto_id INTEGER NOT NULL REFERENCES cryptos(id),
+	PRIMARY KEY (exchange_id, crypto_id)
+);
+
+CREATE TABLE IF NOT EXISTS fear_index (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	source TEXT NOT NULL,
+	target TEXT,
+	date DATETIME NOT NULL,
+	value REAL NOT NULL,
+	UNIQUE(source, target, date)
+);
+
+INSERT OR IGNORE INTO exchanges (name) VALUES ('Binance');
@@ -0,0 +1,16 @@
+-- Execuções simuladas do traderBot em modo paper trading
+CREATE TABLE IF NOT EXISTS paper_trades (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_id TEXT NOT NULL,
+	symbol TEXT NOT NULL,
+	side TEXT NOT NULL,
+	quantity REAL NOT NULL,
+	price REAL NOT NULL,
+	quote_quantity REAL NOT NULL,
+	fee REAL NOT NULL DEFAULT 0,
+	base_balance REAL NOT NULL,
+	quote_balance REAL NOT NULL,
+	executed_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_paper_trades_run_id ON paper_trades (run_id);
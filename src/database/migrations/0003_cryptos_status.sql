@@ -0,0 +1,30 @@
+-- Status de negociação do par na Binance (TRADING, BREAK, DELISTED, ...)
+ALTER TABLE cryptos ADD COLUMN status TEXT;
+
+-- Bancos criados antes das migrações podem não ter a restrição UNIQUE em
+-- symbol e ter o mesmo ativo mais de uma vez. Mantém o registro de menor id e
+-- aponta para ele os vínculos das duplicatas (ignorando os que já existem)
+UPDATE OR IGNORE exchanges_cryptos
+SET crypto_id = (
+	SELECT MIN(kept.id) FROM cryptos kept
+	WHERE kept.symbol = (SELECT symbol FROM cryptos WHERE id = exchanges_cryptos.crypto_id)
+)
+WHERE crypto_id IN (
+	SELECT id FROM cryptos WHERE id NOT IN (SELECT MIN(id) FROM cryptos GROUP BY symbol)
+);
+
+-- Vínculos que já existiam para o registro mantido, e repetidos em tabelas sem
+-- a chave primária
+DELETE FROM exchanges_cryptos
+WHERE crypto_id IN (
+	SELECT id FROM cryptos WHERE id NOT IN (SELECT MIN(id) FROM cryptos GROUP BY symbol)
+);
+DELETE FROM exchanges_cryptos
+WHERE rowid NOT IN (
+	SELECT MIN(rowid) FROM exchanges_cryptos GROUP BY exchange_id, crypto_id
+);
+
+DELETE FROM cryptos
+WHERE id NOT IN (SELECT MIN(id) FROM cryptos GROUP BY symbol);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cryptos_symbol ON cryptos (symbol);
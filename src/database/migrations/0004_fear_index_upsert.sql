@@ -0,0 +1,16 @@
+-- Versões anteriores gravavam a data no fuso local de quem importou e os
+-- importadores passam a gravar em UTC. O fuso de origem não fica no banco,
+-- então as linhas existentes são apenas marcadas com local_time = 1 e
+-- convertidas pelo -Migrate -fearLegacyTZ <fuso> (sentiment.ConvertLocalDates)
+ALTER TABLE fear_index ADD COLUMN local_time INTEGER NOT NULL DEFAULT 0;
+UPDATE fear_index SET local_time = 1;
+
+-- A restrição UNIQUE(source, target, date) não impede duplicatas com target
+-- NULL (índice de mercado). Remove as duplicatas mantendo o registro mais recente
+DELETE FROM fear_index
+WHERE id NOT IN (
+	SELECT MAX(id) FROM fear_index GROUP BY source, COALESCE(target, ''), date
+);
+
+-- Chave usada pelo INSERT ... ON CONFLICT dos importadores de sentimento
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fear_index_source_target_date ON fear_index (source, COALESCE(target, ''), date);
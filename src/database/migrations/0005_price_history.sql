@@ -0,0 +1,18 @@
+-- Klines recentes gravados pelo getDailyPrices, com os valores da API sem arredondamento
+CREATE TABLE IF NOT EXISTS price_history (
+	crypto_id INTEGER NOT NULL REFERENCES cryptos(id),
+	exchange_id INTEGER NOT NULL REFERENCES exchanges(id),
+	interval TEXT NOT NULL,
+	open_time INTEGER NOT NULL,
+	close_time INTEGER NOT NULL,
+	open REAL NOT NULL,
+	high REAL NOT NULL,
+	low REAL NOT NULL,
+	close REAL NOT NULL,
+	volume REAL NOT NULL,
+	quote_asset_volume REAL NOT NULL,
+	number_of_trades INTEGER NOT NULL,
+	taker_buy_base_volume REAL NOT NULL,
+	taker_buy_quote_volume REAL NOT NULL,
+	PRIMARY KEY (crypto_id, exchange_id, interval, open_time)
+);
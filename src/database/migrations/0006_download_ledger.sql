@@ -0,0 +1,16 @@
+-- Tentativas de download dos arquivos do data.binance.vision (substitui o offline_links.txt)
+CREATE TABLE IF NOT EXISTS download_ledger (
+	url TEXT PRIMARY KEY,
+	symbol TEXT NOT NULL,
+	-- Dia (YYYY-MM-DD) ou mês (YYYY-MM) do arquivo
+	date TEXT NOT NULL,
+	-- Status HTTP da última tentativa (NULL em erro de rede)
+	http_status INTEGER,
+	error TEXT,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_attempt DATETIME NOT NULL,
+	-- SHA-256 conferido com o .CHECKSUM publicado pela Binance
+	sha256 TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_download_ledger_symbol_date ON download_ledger (symbol, date);
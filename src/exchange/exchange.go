@@ -0,0 +1,103 @@
+package exchange
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+)
+
+// Lado da ordem (compra ou venda)
+type Side string
+
+const (
+	SideBuy  Side = "BUY"
+	SideSell Side = "SELL"
+)
+
+// Fill representa uma ordem executada (real ou simulada)
+type Fill struct {
+	Symbol        string
+	Side          Side
+	Quantity      float64
+	Price         float64
+	QuoteQuantity float64
+	Fee           float64
+	ExecutedAt    time.Time
+}
+
+// Exchange é o destino das ordens do bot
+type Exchange interface {
+	// ExecuteOrder executa uma ordem a mercado. lastPrice é o fechamento do
+	// último kline conhecido, usado pelas implementações simuladas.
+	ExecuteOrder(symbol string, side Side, quantity float64, lastPrice float64) (*Fill, error)
+}
+
+// Binance envia ordens reais a mercado para a Binance
+type Binance struct {
+	client *binance.Client
+}
+
+func NewBinance(client *binance.Client) *Binance {
+	return &Binance{client: client}
+}
+
+func (b *Binance) ExecuteOrder(symbol string, side Side, quantity float64, lastPrice float64) (*Fill, error) {
+	order, err := b.client.NewCreateOrderService().
+		Symbol(symbol).
+		Side(binance.SideType(side)).
+		Type(binance.OrderTypeMarket).
+		Quantity(fmt.Sprintf("%f", quantity)).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao executar ordem: %v", err)
+	}
+
+	fmt.Println("✅ Ordem executada:", order)
+
+	fill := &Fill{
+		Symbol:     symbol,
+		Side:       side,
+		Quantity:   quantity,
+		Price:      lastPrice,
+		ExecutedAt: time.UnixMilli(order.TransactTime),
+	}
+	fmt.Sscanf(order.ExecutedQuantity, "%g", &fill.Quantity)
+	fmt.Sscanf(order.CummulativeQuoteQuantity, "%g", &fill.QuoteQuantity)
+	if fill.Quantity > 0 && fill.QuoteQuantity > 0 {
+		fill.Price = fill.QuoteQuantity / fill.Quantity
+	}
+	return fill, nil
+}
+
+// Separa o par de trading em ativo base e ativo de cotação (ex: BTCUSDT -> BTC, USDT)
+func SplitSymbol(symbol string) (string, string) {
+	for _, quote := range []string{"USDT", "USDC", "FDUSD", "BUSD", "BTC", "ETH", "BNB"} {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote), quote
+		}
+	}
+	return symbol, ""
+}
+
+// QuoteAsset retorna o ativo de cotação comum a todos os pares (ex: USDT).
+// Falha se algum par não tiver cotação conhecida ou se as cotações diferirem.
+func QuoteAsset(symbols []string) (string, error) {
+	quoteAsset := ""
+	for _, symbol := range symbols {
+		_, quote := SplitSymbol(symbol)
+		if quote == "" {
+			return "", fmt.Errorf("ativo de cotação desconhecido no par %s", symbol)
+		}
+		if quoteAsset != "" && quote != quoteAsset {
+			return "", fmt.Errorf("pares com ativos de cotação diferentes: %s e %s", quoteAsset, quote)
+		}
+		quoteAsset = quote
+	}
+	if quoteAsset == "" {
+		return "", fmt.Errorf("nenhum par informado")
+	}
+	return quoteAsset, nil
+}
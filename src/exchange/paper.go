@@ -0,0 +1,145 @@
+package exchange
+
+import (
+	"app/src/database"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Paper simula uma exchange: preenche as ordens no fechamento do último kline,
+// controla os saldos em memória e registra cada execução no SQLite.
+type Paper struct {
+	mu       sync.Mutex
+	db       *sql.DB
+	runID    string
+	balances map[string]float64
+	fills    []Fill
+	feeRate  float64
+	slippage float64
+	now      func() time.Time
+}
+
+// NewPaper cria uma exchange simulada com saldo inicial no ativo de cotação.
+// Se db for nil as execuções ficam apenas em memória.
+func NewPaper(db *sql.DB, quoteAsset string, initialBalance float64) (*Paper, error) {
+	if db != nil {
+		if _, err := database.Migrate(db); err != nil {
+			return nil, fmt.Errorf("erro ao garantir tabela paper_trades: %w", err)
+		}
+	}
+
+	return &Paper{
+		db:       db,
+		runID:    time.Now().UTC().Format("20060102T150405"),
+		balances: map[string]float64{quoteAsset: initialBalance},
+		now:      func() time.Time { return time.Now().UTC() },
+	}, nil
+}
+
+// SetCosts define a taxa cobrada sobre o valor negociado (0.001 = 0,1%) e o
+// slippage aplicado contra o preço de execução (0.0005 = 0,05%).
+func (p *Paper) SetCosts(feeRate, slippage float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.feeRate = feeRate
+	p.slippage = slippage
+}
+
+// SetClock substitui o relógio usado para datar as execuções (ex: backtest)
+func (p *Paper) SetClock(now func() time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.now = now
+}
+
+func (p *Paper) ExecuteOrder(symbol string, side Side, quantity float64, lastPrice float64) (*Fill, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if quantity <= 0 {
+		return nil, fmt.Errorf("quantidade inválida: %f", quantity)
+	}
+	if lastPrice <= 0 {
+		return nil, fmt.Errorf("preço inválido para %s: %f", symbol, lastPrice)
+	}
+
+	base, quote := SplitSymbol(symbol)
+
+	var price, quoteQuantity, fee float64
+	switch side {
+	case SideBuy:
+		price = lastPrice * (1 + p.slippage)
+		quoteQuantity = quantity * price
+		fee = quoteQuantity * p.feeRate
+		if p.balances[quote] < quoteQuantity+fee {
+			return nil, fmt.Errorf("saldo insuficiente de %s: %f < %f", quote, p.balances[quote], quoteQuantity+fee)
+		}
+		p.balances[quote] -= quoteQuantity + fee
+		p.balances[base] += quantity
+	case SideSell:
+		price = lastPrice * (1 - p.slippage)
+		quoteQuantity = quantity * price
+		fee = quoteQuantity * p.feeRate
+		if p.balances[base] < quantity {
+			return nil, fmt.Errorf("saldo insuficiente de %s: %f < %f", base, p.balances[base], quantity)
+		}
+		p.balances[base] -= quantity
+		p.balances[quote] += quoteQuantity - fee
+	default:
+		return nil, fmt.Errorf("lado de ordem inválido: %s", side)
+	}
+
+	fill := Fill{
+		Symbol:        symbol,
+		Side:          side,
+		Quantity:      quantity,
+		Price:         price,
+		QuoteQuantity: quoteQuantity,
+		Fee:           fee,
+		ExecutedAt:    p.now(),
+	}
+	p.fills = append(p.fills, fill)
+
+	if p.db != nil {
+		if err := p.insertFill(fill, p.balances[base], p.balances[quote]); err != nil {
+			return &fill, fmt.Errorf("ordem simulada executada mas não registrada: %w", err)
+		}
+		fmt.Printf("🧪 Ordem simulada: %s %f %s @ %f | saldo %s: %f | saldo %s: %f\n",
+			side, quantity, symbol, price, base, p.balances[base], quote, p.balances[quote])
+	}
+	return &fill, nil
+}
+
+// Balances retorna uma cópia dos saldos atuais
+func (p *Paper) Balances() map[string]float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	balances := make(map[string]float64, len(p.balances))
+	for asset, amount := range p.balances {
+		balances[asset] = amount
+	}
+	return balances
+}
+
+// Fills retorna as execuções simuladas até o momento
+func (p *Paper) Fills() []Fill {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Fill(nil), p.fills...)
+}
+
+func (p *Paper) insertFill(fill Fill, baseBalance, quoteBalance float64) error {
+	_, err := p.db.Exec(
+		`INSERT INTO paper_trades (run_id, symbol, side, quantity, price, quote_quantity, fee, base_balance, quote_balance, executed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.runID, fill.Symbol, string(fill.Side), fill.Quantity, fill.Price, fill.QuoteQuantity, fill.Fee,
+		baseBalance, quoteBalance, fill.ExecutedAt.Format("2006-01-02 15:04:05"),
+	)
+	return err
+}
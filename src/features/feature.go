@@ -0,0 +1,208 @@
+package features
+
+import (
+	"app/src/models"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Indicator é calculado candle a candle, em ordem cronológica, sobre um único
+// símbolo. Cada chamada de Update retorna um valor por coluna de Columns();
+// enquanto não houver histórico suficiente os valores são NaN.
+type Indicator interface {
+	Columns() []string
+	// Quantidade de candles anteriores necessários para o primeiro valor confiável
+	Warmup() int
+	Update(candle models.Candle) []float64
+}
+
+// Factory cria um indicador a partir dos argumentos da spec (ex: "macd:12:26:9" -> [12 26 9])
+type Factory func(args Args) (Indicator, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register adiciona um indicador ao registro. Deve ser chamado em init().
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("indicador já registrado: %s", name))
+	}
+	registry[name] = factory
+}
+
+// Names lista os indicadores registrados em ordem alfabética
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Args são os argumentos posicionais de um indicador na spec
+type Args []float64
+
+// Int lê o argumento i, usando defaultValue se ausente
+func (a Args) Int(i int, defaultValue int) (int, error) {
+	if i >= len(a) {
+		return defaultValue, nil
+	}
+	if a[i] != math.Trunc(a[i]) || a[i] < 1 {
+		return 0, fmt.Errorf("argumento %d deve ser um inteiro positivo: %v", i+1, a[i])
+	}
+	return int(a[i]), nil
+}
+
+// Float lê o argumento i, usando defaultValue se ausente
+func (a Args) Float(i int, defaultValue float64) float64 {
+	if i >= len(a) {
+		return defaultValue
+	}
+	return a[i]
+}
+
+// Spec é um indicador da spec de features, ex: {Name: "ema", Args: [50]}
+type Spec struct {
+	Name string
+	Args Args
+}
+
+// ParseSpec converte "sma:20,ema:50,rsi:14,macd:12:26:9,bb:20:2" em specs.
+// Argumentos omitidos usam os valores padrão de cada indicador.
+func ParseSpec(raw string) ([]Spec, error) {
+	var specs []Spec
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		spec := Spec{Name: strings.ToLower(strings.TrimSpace(parts[0]))}
+		for _, rawArg := range parts[1:] {
+			arg, err := strconv.ParseFloat(strings.TrimSpace(rawArg), 64)
+			if err != nil {
+				return nil, fmt.Errorf("argumento inválido em %q: %w", item, err)
+			}
+			spec.Args = append(spec.Args, arg)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// Set agrupa os indicadores de uma spec para um símbolo
+type Set struct {
+	indicators []Indicator
+	columns    []string
+	warmup     int
+}
+
+// NewSet cria uma nova instância (com estado zerado) de cada indicador da spec
+func NewSet(specs []Spec) (*Set, error) {
+	set := &Set{}
+	seen := make(map[string]bool)
+
+	for _, spec := range specs {
+		registryMu.RLock()
+		factory, ok := registry[spec.Name]
+		registryMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("indicador desconhecido: %s (disponíveis: %s)", spec.Name, strings.Join(Names(), ", "))
+		}
+
+		indicator, err := factory(spec.Args)
+		if err != nil {
+			return nil, fmt.Errorf("indicador %s: %w", spec.Name, err)
+		}
+
+		for _, column := range indicator.Columns() {
+			if seen[column] {
+				return nil, fmt.Errorf("coluna duplicada na spec de features: %s", column)
+			}
+			seen[column] = true
+		}
+
+		set.indicators = append(set.indicators, indicator)
+		set.columns = append(set.columns, indicator.Columns()...)
+		if indicator.Warmup() > set.warmup {
+			set.warmup = indicator.Warmup()
+		}
+	}
+	return set, nil
+}
+
+// Columns lista as colunas de todos os indicadores, na ordem da spec
+func (s *Set) Columns() []string {
+	return s.columns
+}
+
+// Warmup é o maior warmup entre os indicadores
+func (s *Set) Warmup() int {
+	return s.warmup
+}
+
+// Update alimenta todos os indicadores com o candle e retorna os valores na ordem de Columns()
+func (s *Set) Update(candle models.Candle) []float64 {
+	values := make([]float64, 0, len(s.columns))
+	for _, indicator := range s.indicators {
+		values = append(values, indicator.Update(candle)...)
+	}
+	return values
+}
+
+// Empty retorna um NaN por coluna, usado quando o candle do símbolo está ausente
+func (s *Set) Empty() []float64 {
+	values := make([]float64, len(s.columns))
+	for i := range values {
+		values[i] = math.NaN()
+	}
+	return values
+}
+
+// Format converte um valor para o CSV, com NaN para valores indefinidos
+func Format(value float64) string {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// columnName monta o nome da coluna com os argumentos, ex: ("sma", 20) -> "sma_20"
+func columnName(name string, args ...float64) string {
+	parts := []string{name}
+	for _, arg := range args {
+		parts = append(parts, strconv.FormatFloat(arg, 'f', -1, 64))
+	}
+	return strings.Join(parts, "_")
+}
+
+// String devolve a spec no formato aceito por ParseSpec, ex: "macd:12:26:9"
+func (s Spec) String() string {
+	parts := []string{s.Name}
+	for _, arg := range s.Args {
+		parts = append(parts, strconv.FormatFloat(arg, 'f', -1, 64))
+	}
+	return strings.Join(parts, ":")
+}
+
+// FormatSpec normaliza uma lista de specs (minúsculas, sem espaços), ex: "sma:20,rsi:14"
+func FormatSpec(specs []Spec) string {
+	parts := make([]string, len(specs))
+	for i, spec := range specs {
+		parts[i] = spec.String()
+	}
+	return strings.Join(parts, ",")
+}
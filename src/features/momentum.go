@@ -0,0 +1,148 @@
+package features
+
+import (
+	"app/src/models"
+	"math"
+)
+
+func init() {
+	Register("rsi", newRSIIndicator)
+	Register("atr", newATRIndicator)
+	Register("ret", newReturnIndicator)
+	Register("vol", newVolatilityIndicator)
+}
+
+// rsi:N índice de força relativa com suavização de Wilder (padrão 14)
+type rsiIndicator struct {
+	period    int
+	gain      *ema
+	loss      *ema
+	prevClose float64
+	hasPrev   bool
+}
+
+func newRSIIndicator(args Args) (Indicator, error) {
+	period, err := args.Int(0, 14)
+	if err != nil {
+		return nil, err
+	}
+	return &rsiIndicator{period: period, gain: newWilder(period), loss: newWilder(period)}, nil
+}
+
+func (r *rsiIndicator) Columns() []string {
+	return []string{columnName("rsi", float64(r.period))}
+}
+
+func (r *rsiIndicator) Warmup() int { return 5*r.period + 1 }
+
+func (r *rsiIndicator) Update(candle models.Candle) []float64 {
+	if !r.hasPrev {
+		r.prevClose, r.hasPrev = candle.Close, true
+		return []float64{math.NaN()}
+	}
+	change := candle.Close - r.prevClose
+	r.prevClose = candle.Close
+
+	gain := r.gain.push(math.Max(change, 0))
+	loss := r.loss.push(math.Max(-change, 0))
+	if math.IsNaN(gain) {
+		return []float64{math.NaN()}
+	}
+	if loss == 0 {
+		return []float64{100}
+	}
+	return []float64{100 - 100/(1+gain/loss)}
+}
+
+// atr:N average true range com suavização de Wilder (padrão 14)
+type atrIndicator struct {
+	period    int
+	tr        *ema
+	prevClose float64
+	hasPrev   bool
+}
+
+func newATRIndicator(args Args) (Indicator, error) {
+	period, err := args.Int(0, 14)
+	if err != nil {
+		return nil, err
+	}
+	return &atrIndicator{period: period, tr: newWilder(period)}, nil
+}
+
+func (a *atrIndicator) Columns() []string {
+	return []string{columnName("atr", float64(a.period))}
+}
+
+func (a *atrIndicator) Warmup() int { return 5*a.period + 1 }
+
+func (a *atrIndicator) Update(candle models.Candle) []float64 {
+	trueRange := candle.High - candle.Low
+	if a.hasPrev {
+		trueRange = math.Max(trueRange, math.Max(math.Abs(candle.High-a.prevClose), math.Abs(candle.Low-a.prevClose)))
+	}
+	a.prevClose, a.hasPrev = candle.Close, true
+	return []float64{a.tr.push(trueRange)}
+}
+
+// ret:N retorno percentual do fechamento em relação a N candles atrás (padrão 1)
+type returnIndicator struct {
+	period int
+	closes *rollingWindow
+}
+
+func newReturnIndicator(args Args) (Indicator, error) {
+	period, err := args.Int(0, 1)
+	if err != nil {
+		return nil, err
+	}
+	// Guarda o fechamento atual e os N anteriores
+	return &returnIndicator{period: period, closes: newRollingWindow(period + 1)}, nil
+}
+
+func (r *returnIndicator) Columns() []string {
+	return []string{columnName("ret", float64(r.period))}
+}
+
+func (r *returnIndicator) Warmup() int { return r.period }
+
+func (r *returnIndicator) Update(candle models.Candle) []float64 {
+	r.closes.push(candle.Close)
+	previous := r.closes.oldest()
+	if math.IsNaN(previous) || previous == 0 {
+		return []float64{math.NaN()}
+	}
+	return []float64{(candle.Close/previous - 1) * 100}
+}
+
+// vol:N volatilidade: desvio padrão dos log-retornos dos últimos N candles (padrão 60)
+type volatilityIndicator struct {
+	period    int
+	returns   *rollingWindow
+	prevClose float64
+	hasPrev   bool
+}
+
+func newVolatilityIndicator(args Args) (Indicator, error) {
+	period, err := args.Int(0, 60)
+	if err != nil {
+		return nil, err
+	}
+	return &volatilityIndicator{period: period, returns: newRollingWindow(period)}, nil
+}
+
+func (v *volatilityIndicator) Columns() []string {
+	return []string{columnName("vol", float64(v.period))}
+}
+
+func (v *volatilityIndicator) Warmup() int { return v.period + 1 }
+
+func (v *volatilityIndicator) Update(candle models.Candle) []float64 {
+	if !v.hasPrev || v.prevClose <= 0 || candle.Close <= 0 {
+		v.prevClose, v.hasPrev = candle.Close, true
+		return []float64{v.returns.std()}
+	}
+	v.returns.push(math.Log(candle.Close / v.prevClose))
+	v.prevClose = candle.Close
+	return []float64{v.returns.std()}
+}
@@ -0,0 +1,154 @@
+package features
+
+import (
+	"app/src/models"
+	"fmt"
+	"math"
+)
+
+func init() {
+	Register("sma", newSMAIndicator)
+	Register("ema", newEMAIndicator)
+	Register("macd", newMACDIndicator)
+	Register("bb", newBollingerIndicator)
+}
+
+// sma:N média simples do fechamento (padrão 20)
+type smaIndicator struct {
+	period int
+	window *rollingWindow
+}
+
+func newSMAIndicator(args Args) (Indicator, error) {
+	period, err := args.Int(0, 20)
+	if err != nil {
+		return nil, err
+	}
+	return &smaIndicator{period: period, window: newRollingWindow(period)}, nil
+}
+
+func (s *smaIndicator) Columns() []string {
+	return []string{columnName("sma", float64(s.period))}
+}
+
+func (s *smaIndicator) Warmup() int { return s.period }
+
+func (s *smaIndicator) Update(candle models.Candle) []float64 {
+	s.window.push(candle.Close)
+	return []float64{s.window.mean()}
+}
+
+// ema:N média exponencial do fechamento (padrão 20)
+type emaIndicator struct {
+	period int
+	ema    *ema
+}
+
+func newEMAIndicator(args Args) (Indicator, error) {
+	period, err := args.Int(0, 20)
+	if err != nil {
+		return nil, err
+	}
+	return &emaIndicator{period: period, ema: newEMA(period)}, nil
+}
+
+func (e *emaIndicator) Columns() []string {
+	return []string{columnName("ema", float64(e.period))}
+}
+
+// A EMA depende de todo o histórico; após 5 períodos o peso do início é desprezível
+func (e *emaIndicator) Warmup() int { return 5 * e.period }
+
+func (e *emaIndicator) Update(candle models.Candle) []float64 {
+	return []float64{e.ema.push(candle.Close)}
+}
+
+// macd:FAST:SLOW:SIGNAL linha MACD, linha de sinal e histograma (padrão 12:26:9)
+type macdIndicator struct {
+	fastPeriod, slowPeriod, signalPeriod int
+	fast, slow, signal                   *ema
+}
+
+func newMACDIndicator(args Args) (Indicator, error) {
+	fastPeriod, err := args.Int(0, 12)
+	if err != nil {
+		return nil, err
+	}
+	slowPeriod, err := args.Int(1, 26)
+	if err != nil {
+		return nil, err
+	}
+	signalPeriod, err := args.Int(2, 9)
+	if err != nil {
+		return nil, err
+	}
+	if fastPeriod >= slowPeriod {
+		return nil, fmt.Errorf("período rápido (%d) deve ser menor que o lento (%d)", fastPeriod, slowPeriod)
+	}
+	return &macdIndicator{
+		fastPeriod:   fastPeriod,
+		slowPeriod:   slowPeriod,
+		signalPeriod: signalPeriod,
+		fast:         newEMA(fastPeriod),
+		slow:         newEMA(slowPeriod),
+		signal:       newEMA(signalPeriod),
+	}, nil
+}
+
+func (m *macdIndicator) Columns() []string {
+	args := []float64{float64(m.fastPeriod), float64(m.slowPeriod), float64(m.signalPeriod)}
+	return []string{
+		columnName("macd", args...),
+		columnName("macd_signal", args...),
+		columnName("macd_hist", args...),
+	}
+}
+
+func (m *macdIndicator) Warmup() int { return 5*m.slowPeriod + m.signalPeriod }
+
+func (m *macdIndicator) Update(candle models.Candle) []float64 {
+	fast := m.fast.push(candle.Close)
+	slow := m.slow.push(candle.Close)
+	if math.IsNaN(slow) {
+		return []float64{math.NaN(), math.NaN(), math.NaN()}
+	}
+	macd := fast - slow
+	signal := m.signal.push(macd)
+	return []float64{macd, signal, macd - signal}
+}
+
+// bb:N:K bandas de Bollinger com K desvios padrão (padrão 20:2)
+type bollingerIndicator struct {
+	period int
+	k      float64
+	window *rollingWindow
+}
+
+func newBollingerIndicator(args Args) (Indicator, error) {
+	period, err := args.Int(0, 20)
+	if err != nil {
+		return nil, err
+	}
+	k := args.Float(1, 2)
+	if k <= 0 {
+		return nil, fmt.Errorf("número de desvios deve ser positivo: %v", k)
+	}
+	return &bollingerIndicator{period: period, k: k, window: newRollingWindow(period)}, nil
+}
+
+func (b *bollingerIndicator) Columns() []string {
+	return []string{
+		columnName("bb_upper", float64(b.period), b.k),
+		columnName("bb_middle", float64(b.period), b.k),
+		columnName("bb_lower", float64(b.period), b.k),
+	}
+}
+
+func (b *bollingerIndicator) Warmup() int { return b.period }
+
+func (b *bollingerIndicator) Update(candle models.Candle) []float64 {
+	b.window.push(candle.Close)
+	middle := b.window.mean()
+	deviation := b.k * b.window.std()
+	return []float64{middle + deviation, middle, middle - deviation}
+}
@@ -0,0 +1,93 @@
+package features
+
+import (
+	"app/src/models"
+	"math"
+)
+
+func init() {
+	Register("vwap", newVWAPIndicator)
+	Register("obv", newOBVIndicator)
+}
+
+// vwap:N preço médio ponderado pelo volume (preço típico) dos últimos N candles (padrão 60)
+type vwapIndicator struct {
+	period      int
+	priceVolume *rollingWindow
+	volume      *rollingWindow
+}
+
+func newVWAPIndicator(args Args) (Indicator, error) {
+	period, err := args.Int(0, 60)
+	if err != nil {
+		return nil, err
+	}
+	return &vwapIndicator{
+		period:      period,
+		priceVolume: newRollingWindow(period),
+		volume:      newRollingWindow(period),
+	}, nil
+}
+
+func (v *vwapIndicator) Columns() []string {
+	return []string{columnName("vwap", float64(v.period))}
+}
+
+func (v *vwapIndicator) Warmup() int { return v.period }
+
+func (v *vwapIndicator) Update(candle models.Candle) []float64 {
+	typical := (candle.High + candle.Low + candle.Close) / 3
+	v.priceVolume.push(typical * candle.Volume)
+	v.volume.push(candle.Volume)
+	if !v.volume.full() {
+		return []float64{math.NaN()}
+	}
+	if v.volume.sum <= 0 {
+		// Janela sem negociação
+		return []float64{typical}
+	}
+	return []float64{v.priceVolume.sum / v.volume.sum}
+}
+
+// obv:N on-balance volume acumulado nos últimos N candles (padrão 60). A janela
+// evita que o valor dependa de onde o histórico começa.
+type obvIndicator struct {
+	period    int
+	flows     *rollingWindow
+	prevClose float64
+	hasPrev   bool
+}
+
+func newOBVIndicator(args Args) (Indicator, error) {
+	period, err := args.Int(0, 60)
+	if err != nil {
+		return nil, err
+	}
+	return &obvIndicator{period: period, flows: newRollingWindow(period)}, nil
+}
+
+func (o *obvIndicator) Columns() []string {
+	return []string{columnName("obv", float64(o.period))}
+}
+
+func (o *obvIndicator) Warmup() int { return o.period + 1 }
+
+func (o *obvIndicator) Update(candle models.Candle) []float64 {
+	if !o.hasPrev {
+		o.prevClose, o.hasPrev = candle.Close, true
+		return []float64{math.NaN()}
+	}
+	flow := 0.0
+	switch {
+	case candle.Close > o.prevClose:
+		flow = candle.Volume
+	case candle.Close < o.prevClose:
+		flow = -candle.Volume
+	}
+	o.prevClose = candle.Close
+	o.flows.push(flow)
+	if !o.flows.full() {
+		return []float64{math.NaN()}
+	}
+	return []float64{o.flows.sum}
+}
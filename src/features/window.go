@@ -0,0 +1,102 @@
+package features
+
+import "math"
+
+// rollingWindow guarda os últimos size valores com soma e soma dos quadrados
+type rollingWindow struct {
+	values []float64
+	next   int
+	count  int
+	sum    float64
+	sumSq  float64
+}
+
+func newRollingWindow(size int) *rollingWindow {
+	return &rollingWindow{values: make([]float64, size)}
+}
+
+func (w *rollingWindow) push(value float64) {
+	if w.count == len(w.values) {
+		old := w.values[w.next]
+		w.sum -= old
+		w.sumSq -= old * old
+	} else {
+		w.count++
+	}
+	w.values[w.next] = value
+	w.sum += value
+	w.sumSq += value * value
+	w.next = (w.next + 1) % len(w.values)
+}
+
+func (w *rollingWindow) full() bool {
+	return w.count == len(w.values)
+}
+
+func (w *rollingWindow) mean() float64 {
+	if !w.full() {
+		return math.NaN()
+	}
+	return w.sum / float64(w.count)
+}
+
+// std é o desvio padrão populacional da janela
+func (w *rollingWindow) std() float64 {
+	if !w.full() {
+		return math.NaN()
+	}
+	n := float64(w.count)
+	mean := w.sum / n
+	variance := w.sumSq/n - mean*mean
+	if variance < 0 {
+		// Erro de arredondamento em janelas quase constantes
+		variance = 0
+	}
+	return math.Sqrt(variance)
+}
+
+// oldest retorna o valor mais antigo da janela cheia
+func (w *rollingWindow) oldest() float64 {
+	if !w.full() {
+		return math.NaN()
+	}
+	return w.values[w.next]
+}
+
+// ema é uma média móvel exponencial iniciada pela média simples dos primeiros period valores
+type ema struct {
+	period int
+	alpha  float64
+	seed   *rollingWindow
+	value  float64
+	ready  bool
+}
+
+func newEMA(period int) *ema {
+	return &ema{
+		period: period,
+		alpha:  2 / float64(period+1),
+		seed:   newRollingWindow(period),
+	}
+}
+
+// newWilder cria a média suavizada de Wilder (alpha = 1/period) usada por RSI e ATR
+func newWilder(period int) *ema {
+	e := newEMA(period)
+	e.alpha = 1 / float64(period)
+	return e
+}
+
+func (e *ema) push(value float64) float64 {
+	if e.ready {
+		e.value += e.alpha * (value - e.value)
+		return e.value
+	}
+	e.seed.push(value)
+	if !e.seed.full() {
+		return math.NaN()
+	}
+	e.value = e.seed.mean()
+	e.ready = true
+	return e.value
+}
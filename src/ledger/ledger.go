@@ -0,0 +1,222 @@
+package ledger
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config do ledger de downloads
+type Config struct {
+	// Espera antes de tentar novamente uma URL que retornou 404, dobrada a cada
+	// tentativa até MaxNotFoundCooldown. Os arquivos diários são publicados com
+	// atraso e um par pode passar a existir depois, então o 404 não é definitivo.
+	NotFoundCooldown    time.Duration
+	MaxNotFoundCooldown time.Duration
+}
+
+// DefaultConfig tenta novamente um 404 após um dia, chegando a 30 dias
+func DefaultConfig() Config {
+	return Config{
+		NotFoundCooldown:    24 * time.Hour,
+		MaxNotFoundCooldown: 30 * 24 * time.Hour,
+	}
+}
+
+// Entry é a última tentativa de download de uma URL
+type Entry struct {
+	URL    string
+	Symbol string
+	Date   string
+	// Status HTTP da última tentativa (0 em erro de rede)
+	HTTPStatus int
+	Error      string
+	// Tentativas seguidas com o mesmo status da última; uma mudança de status
+	// reinicia a contagem, então o cooldown do 404 só cresce com 404 seguidos
+	Attempts    int
+	LastAttempt time.Time
+	SHA256      string
+}
+
+// Ledger registra no download_ledger cada tentativa de download dos arquivos
+// do data.binance.vision e decide quais URLs devem ser evitadas. Apenas 404
+// é evitado, e só até o fim do cooldown; erros de rede e demais status são
+// tentados novamente na próxima execução.
+type Ledger struct {
+	db     *sql.DB
+	config Config
+	// O SQLite aceita um escritor por vez e os downloads rodam em paralelo
+	mu sync.Mutex
+}
+
+// Open cria o ledger sobre o banco já migrado e importa o offline_links.txt
+// legado de dataDir, quando existir
+func Open(db *sql.DB, config Config, dataDir string) (*Ledger, error) {
+	l := &Ledger{db: db, config: config}
+	if err := l.importOfflineLinks(filepath.Join(dataDir, "offline_links.txt")); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Skip indica se url retornou 404 na última tentativa e ainda está no cooldown
+func (l *Ledger) Skip(url string) (bool, error) {
+	entry, ok, err := l.Get(url)
+	if err != nil || !ok {
+		return false, err
+	}
+	return entry.HTTPStatus == http.StatusNotFound && time.Now().Before(entry.LastAttempt.Add(l.Cooldown(entry.Attempts))), nil
+}
+
+// Cooldown é a espera após attempts respostas 404 seguidas de uma URL
+func (l *Ledger) Cooldown(attempts int) time.Duration {
+	cooldown := l.config.NotFoundCooldown
+	for i := 1; i < attempts && cooldown < l.config.MaxNotFoundCooldown; i++ {
+		cooldown *= 2
+	}
+	return min(cooldown, l.config.MaxNotFoundCooldown)
+}
+
+// Get retorna a última tentativa registrada de url
+func (l *Ledger) Get(url string) (Entry, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var entry Entry
+	var status sql.NullInt64
+	var errorText, sha sql.NullString
+	err := l.db.QueryRow(`
+        SELECT url, symbol, date, http_status, error, attempts, last_attempt, sha256
+        FROM download_ledger
+        WHERE url = ?;
+    `, url).Scan(&entry.URL, &entry.Symbol, &entry.Date, &status, &errorText, &entry.Attempts, &entry.LastAttempt, &sha)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entry, false, nil
+	}
+	if err != nil {
+		return entry, false, fmt.Errorf("erro ao consultar o ledger: %w", err)
+	}
+	entry.HTTPStatus = int(status.Int64)
+	entry.Error = errorText.String
+	entry.SHA256 = sha.String
+	return entry, true, nil
+}
+
+// Record registra uma tentativa de download de url. statusCode é 0 quando a
+// requisição falhou sem resposta; downloadErr e sha256 (do zip verificado)
+// são opcionais. O símbolo e a data vêm do nome do arquivo na URL.
+func (l *Ledger) Record(url string, statusCode int, downloadErr error, sha256 string) error {
+	symbol, date := parseArchiveURL(url)
+	errorText := ""
+	if downloadErr != nil {
+		errorText = downloadErr.Error()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err := l.db.Exec(`
+        INSERT INTO download_ledger (url, symbol, date, http_status, error, attempts, last_attempt, sha256)
+        VALUES (?, ?, ?, NULLIF(?, 0), NULLIF(?, ''), 1, ?, NULLIF(?, ''))
+        ON CONFLICT (url) DO UPDATE SET
+            http_status = excluded.http_status,
+            error = excluded.error,
+            attempts = CASE
+                WHEN COALESCE(download_ledger.http_status, 0) = COALESCE(excluded.http_status, 0) THEN download_ledger.attempts + 1
+                ELSE 1
+            END,
+            last_attempt = excluded.last_attempt,
+            sha256 = COALESCE(excluded.sha256, download_ledger.sha256);
+    `, url, symbol, date, statusCode, errorText, time.Now().UTC(), sha256)
+	if err != nil {
+		return fmt.Errorf("erro ao registrar %s no ledger: %w", url, err)
+	}
+	return nil
+}
+
+// parseArchiveURL extrai o par e a data do nome do arquivo
+// (ex: .../BTCUSDT-1m-2024-01-02.zip → BTCUSDT, 2024-01-02)
+func parseArchiveURL(url string) (symbol, date string) {
+	name := strings.TrimSuffix(path.Base(url), ".zip")
+	parts := strings.Split(name, "-")
+	if len(parts) < 4 {
+		return name, ""
+	}
+	return parts[0], strings.Join(parts[2:], "-")
+}
+
+// importOfflineLinks grava como 404 as URLs do offline_links.txt usado antes
+// do ledger e renomeia o arquivo para offline_links.txt.imported. O arquivo
+// não distingue 404 de erros de rede, então todas passam pelo cooldown e
+// são tentadas novamente depois dele.
+func (l *Ledger) importOfflineLinks(filePath string) error {
+	file, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao abrir %s: %w", filePath, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	var urls []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if url := strings.TrimSpace(scanner.Text()); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	file.Close()
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("erro ao ler %s: %w", filePath, err)
+	}
+
+	tx, err := l.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+        INSERT INTO download_ledger (url, symbol, date, http_status, error, attempts, last_attempt)
+        VALUES (?, ?, ?, ?, ?, 1, ?)
+        ON CONFLICT (url) DO NOTHING;
+    `)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	imported := 0
+	for _, url := range urls {
+		symbol, date := parseArchiveURL(url)
+		result, err := stmt.Exec(url, symbol, date, http.StatusNotFound, "importado do offline_links.txt", info.ModTime().UTC())
+		if err != nil {
+			return fmt.Errorf("erro ao importar %s: %w", url, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			imported++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(filePath, filePath+".imported"); err != nil {
+		return fmt.Errorf("erro ao renomear %s: %w", filePath, err)
+	}
+	log.Printf("📒 %d links do offline_links.txt importados para o ledger de downloads", imported)
+	return nil
+}
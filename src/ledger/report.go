@@ -0,0 +1,125 @@
+package ledger
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+// Summary resume o estado das URLs registradas
+type Summary struct {
+	// Baixadas com sucesso, e quantas com SHA-256 conferido
+	Downloaded int
+	Verified   int
+	// 404 ainda no cooldown e prontos para nova tentativa
+	NotFoundWaiting int
+	NotFoundReady   int
+	// Erros de rede, tentados novamente na próxima execução
+	NetworkErrors int
+	// Demais falhas (outros status ou checksum divergente) por status HTTP
+	OtherFailures map[int]int
+}
+
+// SymbolSummary são as contagens de um par
+type SymbolSummary struct {
+	Symbol     string
+	Downloaded int
+	NotFound   int
+	Failed     int
+}
+
+// Summary calcula o resumo de todas as URLs do ledger
+func (l *Ledger) Summary() (Summary, error) {
+	summary := Summary{OtherFailures: make(map[int]int)}
+	err := l.each(`SELECT url, symbol, date, http_status, error, attempts, last_attempt, sha256 FROM download_ledger;`, func(entry Entry) {
+		switch {
+		case entry.HTTPStatus == http.StatusOK && entry.Error == "":
+			summary.Downloaded++
+			if entry.SHA256 != "" {
+				summary.Verified++
+			}
+		case entry.HTTPStatus == http.StatusNotFound:
+			if time.Now().Before(entry.LastAttempt.Add(l.Cooldown(entry.Attempts))) {
+				summary.NotFoundWaiting++
+			} else {
+				summary.NotFoundReady++
+			}
+		case entry.HTTPStatus == 0:
+			summary.NetworkErrors++
+		default:
+			summary.OtherFailures[entry.HTTPStatus]++
+		}
+	})
+	return summary, err
+}
+
+// Symbols retorna as contagens por par, com mais falhas primeiro
+func (l *Ledger) Symbols(limit int) ([]SymbolSummary, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rows, err := l.db.Query(`
+        SELECT symbol,
+            SUM(CASE WHEN http_status = 200 AND error IS NULL THEN 1 ELSE 0 END) AS downloaded,
+            SUM(CASE WHEN http_status = 404 THEN 1 ELSE 0 END) AS not_found,
+            SUM(CASE WHEN (http_status IS NULL OR http_status <> 200 OR error IS NOT NULL) AND COALESCE(http_status, 0) <> 404 THEN 1 ELSE 0 END) AS failed
+        FROM download_ledger
+        GROUP BY symbol
+        ORDER BY failed DESC, not_found DESC, symbol
+        LIMIT ?;
+    `, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var symbols []SymbolSummary
+	for rows.Next() {
+		var s SymbolSummary
+		if err := rows.Scan(&s.Symbol, &s.Downloaded, &s.NotFound, &s.Failed); err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, s)
+	}
+	return symbols, rows.Err()
+}
+
+// Failures retorna as últimas tentativas sem sucesso que não foram 404
+func (l *Ledger) Failures(limit int) ([]Entry, error) {
+	var entries []Entry
+	err := l.each(`
+        SELECT url, symbol, date, http_status, error, attempts, last_attempt, sha256
+        FROM download_ledger
+        WHERE (http_status IS NULL OR http_status <> 200 OR error IS NOT NULL)
+        AND COALESCE(http_status, 0) <> 404
+        ORDER BY last_attempt DESC
+        LIMIT ?;
+    `, func(entry Entry) { entries = append(entries, entry) }, limit)
+	return entries, err
+}
+
+// each executa a consulta e chama fn para cada registro
+func (l *Ledger) each(query string, fn func(Entry), args ...any) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rows, err := l.db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry Entry
+		var status sql.NullInt64
+		var errorText, sha sql.NullString
+		if err := rows.Scan(&entry.URL, &entry.Symbol, &entry.Date, &status, &errorText, &entry.Attempts, &entry.LastAttempt, &sha); err != nil {
+			return err
+		}
+		entry.HTTPStatus = int(status.Int64)
+		entry.Error = errorText.String
+		entry.SHA256 = sha.String
+		fn(entry)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,13 @@
+package models
+
+// Candle é um kline já convertido para valores numéricos
+type Candle struct {
+	Symbol    string
+	OpenTime  int64
+	CloseTime int64
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    float64
+}
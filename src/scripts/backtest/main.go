@@ -0,0 +1,99 @@
+package backtest
+
+import (
+	"app/src/exchange"
+	"app/src/models"
+	"app/src/strategy"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Config define a simulação a ser executada
+type Config struct {
+	Strategy       string
+	StrategyParams strategy.Params
+	Symbols        []string
+	Start          time.Time
+	End            time.Time
+	Interval       string
+	InitialBalance float64
+	FeeRate        float64
+	Slippage       float64
+}
+
+// DefaultConfig usa as taxas padrão da Binance spot (0,1%) sem slippage
+func DefaultConfig() Config {
+	return Config{
+		Strategy:       "percentChange",
+		Symbols:        []string{"BTCUSDT"},
+		Interval:       "1m",
+		InitialBalance: 1000,
+		FeeRate:        0.001,
+	}
+}
+
+// Main reproduz os klines baixados em DATA_DIR pela mesma estratégia usada no
+// traderBot e gera o relatório de desempenho e o log de trades.
+func Main(config Config) {
+	strat, err := strategy.New(config.Strategy, config.StrategyParams)
+	if err != nil {
+		log.Printf("❌ Erro ao criar estratégia: %v", err)
+		return
+	}
+
+	paper, err := exchange.NewPaper(nil, "USDT", config.InitialBalance)
+	if err != nil {
+		log.Printf("❌ Erro ao criar exchange simulada: %v", err)
+		return
+	}
+	paper.SetCosts(config.FeeRate, config.Slippage)
+
+	var candleTime time.Time
+	paper.SetClock(func() time.Time { return candleTime })
+
+	log.Printf("🚀 Backtest %s | %v | %s até %s | taxa %.4f | slippage %.4f",
+		strat.Name(), config.Symbols, config.Start.Format("2006-01-02"), config.End.Format("2006-01-02"),
+		config.FeeRate, config.Slippage)
+
+	r := newReport(config)
+
+	for day := config.Start; !day.After(config.End); day = day.AddDate(0, 0, 1) {
+		count := streamDay(day, config.Symbols, config.Interval, func(candle models.Candle) {
+			candleTime = time.UnixMilli(candle.CloseTime).UTC()
+			r.updatePrice(candle)
+
+			signal := strat.OnCandle(candle)
+			var side exchange.Side
+			switch signal.Action {
+			case strategy.ActionBuy:
+				side = exchange.SideBuy
+			case strategy.ActionSell:
+				side = exchange.SideSell
+			default:
+				return
+			}
+
+			fill, err := paper.ExecuteOrder(candle.Symbol, side, signal.Quantity, candle.Close)
+			if err != nil {
+				r.rejected++
+				return
+			}
+			r.recordFill(*fill)
+		})
+
+		if count > 0 {
+			r.closeDay()
+		}
+		log.Printf("📅 %s: %d candles", day.Format("2006-01-02"), count)
+	}
+
+	r.print()
+
+	logPath, err := r.writeTradeLog()
+	if err != nil {
+		log.Printf("❌ Erro ao salvar log de trades: %v", err)
+		return
+	}
+	fmt.Printf("📄 Log de trades salvo em: %s\n", logPath)
+}
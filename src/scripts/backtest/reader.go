@@ -0,0 +1,139 @@
+package backtest
+
+import (
+	"app/src/models"
+	"app/src/utils"
+	"bufio"
+	"container/heap"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// symbolFile é um CSV diário de klines lido linha a linha
+type symbolFile struct {
+	symbol  string
+	file    *os.File
+	scanner *bufio.Scanner
+	current models.Candle
+}
+
+// next avança para o próximo candle válido do arquivo
+func (f *symbolFile) next() bool {
+	for f.scanner.Scan() {
+		candle, ok := parseKlineLine(f.symbol, f.scanner.Text())
+		if ok {
+			f.current = candle
+			return true
+		}
+	}
+	if err := f.scanner.Err(); err != nil {
+		log.Printf("⚠️ Erro ao ler %s: %v", f.file.Name(), err)
+	}
+	return false
+}
+
+// candleHeap ordena os arquivos abertos pelo OpenTime do candle atual
+type candleHeap []*symbolFile
+
+func (h candleHeap) Len() int { return len(h) }
+func (h candleHeap) Less(i, j int) bool {
+	if h[i].current.OpenTime == h[j].current.OpenTime {
+		return h[i].symbol < h[j].symbol
+	}
+	return h[i].current.OpenTime < h[j].current.OpenTime
+}
+func (h candleHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *candleHeap) Push(x any)   { *h = append(*h, x.(*symbolFile)) }
+func (h *candleHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// streamDay entrega os candles de todos os símbolos de um dia em ordem
+// cronológica, mantendo apenas uma linha de cada arquivo em memória.
+func streamDay(day time.Time, symbols []string, interval string, onCandle func(models.Candle)) int {
+	dateStr := day.Format("2006-01-02")
+	klineBasePath := filepath.Join(os.Getenv("DATA_DIR"), "data.binance.vision/data/spot/daily/klines")
+
+	h := &candleHeap{}
+	defer func() {
+		for _, f := range *h {
+			f.file.Close()
+		}
+	}()
+
+	for _, symbol := range symbols {
+		filePath := filepath.Join(klineBasePath, symbol, interval, "csv", fmt.Sprintf("%s-%s-%s.csv", symbol, interval, dateStr))
+		file, err := os.Open(filePath)
+		if err != nil {
+			log.Printf("⚠️ Arquivo não encontrado: %s", filePath)
+			continue
+		}
+
+		f := &symbolFile{symbol: symbol, file: file, scanner: bufio.NewScanner(file)}
+		if f.next() {
+			heap.Push(h, f)
+		} else {
+			file.Close()
+		}
+	}
+
+	count := 0
+	for h.Len() > 0 {
+		f := (*h)[0]
+		onCandle(f.current)
+		count++
+
+		if f.next() {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+			f.file.Close()
+		}
+	}
+
+	return count
+}
+
+func parseKlineLine(symbol, line string) (models.Candle, bool) {
+	fields := strings.Split(line, ",")
+	if len(fields) < 6 {
+		return models.Candle{}, false
+	}
+
+	openTime, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		// Cabeçalho ou linha inválida
+		return models.Candle{}, false
+	}
+
+	var closeTime int64
+	if len(fields) > 6 {
+		closeTime, _ = strconv.ParseInt(fields[6], 10, 64)
+	}
+
+	open, _ := strconv.ParseFloat(fields[1], 64)
+	high, _ := strconv.ParseFloat(fields[2], 64)
+	low, _ := strconv.ParseFloat(fields[3], 64)
+	closePrice, _ := strconv.ParseFloat(fields[4], 64)
+	volume, _ := strconv.ParseFloat(fields[5], 64)
+
+	return models.Candle{
+		Symbol:    symbol,
+		OpenTime:  utils.NormalizeTimestampMs(openTime),
+		CloseTime: utils.NormalizeTimestampMs(closeTime),
+		Open:      open,
+		High:      high,
+		Low:       low,
+		Close:     closePrice,
+		Volume:    volume,
+	}, true
+}
@@ -0,0 +1,219 @@
+package backtest
+
+import (
+	"app/src/exchange"
+	"app/src/models"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// trade é uma linha do log de trades
+type trade struct {
+	fill        exchange.Fill
+	realizedPnL float64
+	quote       float64
+	equity      float64
+}
+
+// report acompanha saldo, posições e equity durante a simulação
+type report struct {
+	config Config
+
+	quote     float64
+	positions map[string]float64
+	avgCost   map[string]float64
+	lastPrice map[string]float64
+
+	peakEquity  float64
+	maxDrawdown float64
+	dailyEquity []float64
+
+	trades    []trade
+	wins      int
+	losses    int
+	rejected  int
+	totalFees float64
+}
+
+func newReport(config Config) *report {
+	return &report{
+		config:      config,
+		quote:       config.InitialBalance,
+		positions:   make(map[string]float64),
+		avgCost:     make(map[string]float64),
+		lastPrice:   make(map[string]float64),
+		peakEquity:  config.InitialBalance,
+		dailyEquity: []float64{config.InitialBalance},
+	}
+}
+
+func (r *report) equity() float64 {
+	equity := r.quote
+	for symbol, quantity := range r.positions {
+		equity += quantity * r.lastPrice[symbol]
+	}
+	return equity
+}
+
+// updatePrice marca as posições a mercado e atualiza o drawdown máximo
+func (r *report) updatePrice(candle models.Candle) {
+	r.lastPrice[candle.Symbol] = candle.Close
+	if r.positions[candle.Symbol] == 0 {
+		return
+	}
+
+	equity := r.equity()
+	if equity > r.peakEquity {
+		r.peakEquity = equity
+	}
+	if r.peakEquity > 0 {
+		drawdown := (r.peakEquity - equity) / r.peakEquity
+		if drawdown > r.maxDrawdown {
+			r.maxDrawdown = drawdown
+		}
+	}
+}
+
+// recordFill atualiza posição e preço médio; vendas realizam PnL contra o preço médio
+func (r *report) recordFill(fill exchange.Fill) {
+	r.totalFees += fill.Fee
+
+	realized := 0.0
+	switch fill.Side {
+	case exchange.SideBuy:
+		position := r.positions[fill.Symbol]
+		cost := r.avgCost[fill.Symbol]*position + fill.QuoteQuantity + fill.Fee
+		r.positions[fill.Symbol] = position + fill.Quantity
+		r.avgCost[fill.Symbol] = cost / r.positions[fill.Symbol]
+		r.quote -= fill.QuoteQuantity + fill.Fee
+	case exchange.SideSell:
+		realized = fill.QuoteQuantity - fill.Fee - r.avgCost[fill.Symbol]*fill.Quantity
+		r.positions[fill.Symbol] -= fill.Quantity
+		if r.positions[fill.Symbol] <= 1e-12 {
+			r.positions[fill.Symbol] = 0
+			r.avgCost[fill.Symbol] = 0
+		}
+		r.quote += fill.QuoteQuantity - fill.Fee
+		if realized > 0 {
+			r.wins++
+		} else {
+			r.losses++
+		}
+	}
+
+	r.trades = append(r.trades, trade{
+		fill:        fill,
+		realizedPnL: realized,
+		quote:       r.quote,
+		equity:      r.equity(),
+	})
+}
+
+// closeDay registra a equity no fim do dia, usada no cálculo do Sharpe
+func (r *report) closeDay() {
+	r.dailyEquity = append(r.dailyEquity, r.equity())
+}
+
+// sharpe calcula o Sharpe anualizado dos retornos diários (taxa livre de risco zero)
+func (r *report) sharpe() float64 {
+	if len(r.dailyEquity) < 3 {
+		return 0
+	}
+
+	var returns []float64
+	for i := 1; i < len(r.dailyEquity); i++ {
+		if r.dailyEquity[i-1] > 0 {
+			returns = append(returns, r.dailyEquity[i]/r.dailyEquity[i-1]-1)
+		}
+	}
+
+	mean := 0.0
+	for _, ret := range returns {
+		mean += ret
+	}
+	mean /= float64(len(returns))
+
+	variance := 0.0
+	for _, ret := range returns {
+		variance += (ret - mean) * (ret - mean)
+	}
+	variance /= float64(len(returns) - 1)
+
+	if variance == 0 {
+		return 0
+	}
+	return mean / math.Sqrt(variance) * math.Sqrt(365)
+}
+
+func (r *report) print() {
+	finalEquity := r.equity()
+	pnl := finalEquity - r.config.InitialBalance
+
+	winRate := 0.0
+	if r.wins+r.losses > 0 {
+		winRate = float64(r.wins) / float64(r.wins+r.losses) * 100
+	}
+
+	fmt.Println("\n📊 RESULTADO DO BACKTEST")
+	fmt.Println(strings.Repeat("=", 40))
+	fmt.Printf("Estratégia:          %s\n", r.config.Strategy)
+	fmt.Printf("Período:             %s até %s\n", r.config.Start.Format("2006-01-02"), r.config.End.Format("2006-01-02"))
+	fmt.Printf("Saldo inicial:       %.2f USDT\n", r.config.InitialBalance)
+	fmt.Printf("Equity final:        %.2f USDT\n", finalEquity)
+	fmt.Printf("PnL:                 %.2f USDT (%.2f%%)\n", pnl, pnl/r.config.InitialBalance*100)
+	fmt.Printf("Drawdown máximo:     %.2f%%\n", r.maxDrawdown*100)
+	fmt.Printf("Sharpe (anualizado): %.2f\n", r.sharpe())
+	fmt.Printf("Trades:              %d (%d rejeitados)\n", len(r.trades), r.rejected)
+	fmt.Printf("Win rate:            %.2f%% (%d/%d vendas)\n", winRate, r.wins, r.wins+r.losses)
+	fmt.Printf("Taxas pagas:         %.2f USDT\n", r.totalFees)
+	fmt.Println(strings.Repeat("=", 40))
+}
+
+// writeTradeLog salva todos os trades em DATA_DIR/backtests
+func (r *report) writeTradeLog() (string, error) {
+	dir := filepath.Join(os.Getenv("DATA_DIR"), "backtests")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("erro ao criar diretório: %w", err)
+	}
+
+	fileName := fmt.Sprintf("%s-%s-%s-%s.csv", r.config.Strategy,
+		r.config.Start.Format("2006-01-02"), r.config.End.Format("2006-01-02"),
+		time.Now().UTC().Format("20060102T150405"))
+	filePath := filepath.Join(dir, fileName)
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return "", fmt.Errorf("erro ao criar arquivo CSV: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"time", "symbol", "side", "quantity", "price", "quote_quantity", "fee", "realized_pnl", "quote_balance", "equity"})
+	for _, t := range r.trades {
+		writer.Write([]string{
+			t.fill.ExecutedAt.Format(time.RFC3339),
+			t.fill.Symbol,
+			string(t.fill.Side),
+			formatFloat(t.fill.Quantity),
+			formatFloat(t.fill.Price),
+			formatFloat(t.fill.QuoteQuantity),
+			formatFloat(t.fill.Fee),
+			formatFloat(t.realizedPnL),
+			formatFloat(t.quote),
+			formatFloat(t.equity),
+		})
+	}
+	writer.Flush()
+
+	return filePath, writer.Error()
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -1,7 +1,10 @@
 package disableCryptos
 
 import (
+	"app/src/binanceapi"
 	"app/src/database"
+	"app/src/ledger"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -13,6 +16,20 @@ import (
 	_ "modernc.org/sqlite"
 )
 
+// Cliente dos arquivos do data.binance.vision
+var dataClient = binanceapi.New(binanceapi.DataConfig())
+
+// Resultado da verificação do arquivo diário de um par
+type availability int
+
+const (
+	available availability = iota
+	// 404 confirmado agora ou ainda no cooldown do ledger
+	notFound
+	// Erro de rede ou outro status: não dá para afirmar que o arquivo não existe
+	unknown
+)
+
 // Estrutura para criptomoedas habilitadas
 type crypto struct {
 	ID         int
@@ -21,14 +38,14 @@ type crypto struct {
 	IsEnabled  int
 }
 
-// Função principal para desativar criptos indisponíveis
-func Main(minDate, maxDate string) {
+// Função principal para desativar criptos indisponíveis no intervalo de kline informado
+func Main(minDate, maxDate, interval string) {
 	// Configurar logging
 	log.SetFlags(log.LstdFlags | log.Lmsgprefix)
 	log.SetPrefix("INFO: ")
 
 	log.Printf("🚀 Iniciando verificação de disponibilidade de criptos")
-	log.Printf("📅 Período: %s até %s", minDate, maxDate)
+	log.Printf("📅 Período: %s até %s (%s)", minDate, maxDate, interval)
 
 	// Obter criptos habilitadas
 	cryptos, err := getCryptos()
@@ -42,12 +59,30 @@ func Main(minDate, maxDate string) {
 		return
 	}
 
+	db, err := database.ConnectDatabase()
+	if err != nil {
+		log.Printf("❌ Erro ao abrir o banco de dados: %v", err)
+		return
+	}
+	defer db.Close()
+
+	if _, err := database.Migrate(db); err != nil {
+		log.Printf("❌ Erro ao aplicar migrações: %v", err)
+		return
+	}
+
+	// Tentativas de download anteriores (substitui o offline_links.txt)
+	downloads, err := ledger.Open(db, ledger.DefaultConfig(), os.Getenv("DATA_DIR"))
+	if err != nil {
+		log.Printf("❌ Erro ao abrir o ledger de downloads: %v", err)
+		return
+	}
+
 	log.Printf("📊 Total de criptomoedas a verificar: %d", len(cryptos))
 
 	disabledCryptos := make(map[string]bool)
 	// Verificar cada crypto nas duas datas
 	for index, crypto := range cryptos {
-		httpRequestMaked := false
 		symbol := fmt.Sprintf("%sUSDT", crypto.Symbol)
 
 		if disabledCryptos[symbol] {
@@ -58,13 +93,13 @@ func Main(minDate, maxDate string) {
 		log.Printf("👉 (%d/%d) Verificando %s (ID: %d)", index+1, len(cryptos), symbol, crypto.ID)
 
 		// Verificar disponibilidade na data mínima
-		availableMinDate := checkCryptoAvailability(symbol, "1m", minDate, &httpRequestMaked)
+		availableMinDate := checkCryptoAvailability(downloads, symbol, interval, minDate)
 
 		// Verificar disponibilidade na data máxima
-		availableMaxDate := checkCryptoAvailability(symbol, "1m", maxDate, &httpRequestMaked)
+		availableMaxDate := checkCryptoAvailability(downloads, symbol, interval, maxDate)
 
-		// Se retornou 404 em ambas as datas, desativar a crypto
-		if !availableMinDate || !availableMaxDate {
+		// Só um 404 confirmado desativa a crypto; sem resposta, nada é alterado
+		if availableMinDate == notFound || availableMaxDate == notFound {
 			log.Printf("🚫 %s indisponível em uma das datas. Desativando...", symbol)
 			if err := disableCrypto(crypto.Symbol); err != nil {
 				log.Printf("❌ Erro ao desativar %s: %v", symbol, err)
@@ -72,6 +107,9 @@ func Main(minDate, maxDate string) {
 			disabledCryptos[symbol] = true
 			log.Printf("☐ %s desativada", symbol)
 			continue
+		} else if availableMinDate == unknown || availableMaxDate == unknown {
+			log.Printf("⚠️ Não foi possível verificar %s, mantendo como está", symbol)
+			continue
 		} else {
 			log.Printf("✅ %s está disponível em pelo menos uma das datas", symbol)
 		}
@@ -88,10 +126,16 @@ func Main(minDate, maxDate string) {
 			return
 		}
 
+		// Dias sem resposta não desativam, mas impedem a ativação
+		unverified := 0
 		for i := initialDate; i.Before(time.Now().UTC()) && (i.Before(endDate) || i.Equal(endDate)); i = i.Add(24 * time.Hour) {
 			currentDateStr := i.Format("2006-01-02")
-			isAvailable := checkCryptoAvailability(symbol, "1m", currentDateStr, &httpRequestMaked)
-			if !isAvailable {
+			status := checkCryptoAvailability(downloads, symbol, interval, currentDateStr)
+			if status == unknown {
+				unverified++
+				continue
+			}
+			if status == notFound {
 				if err := disableCrypto(crypto.Symbol); err != nil {
 					log.Printf("❌ Erro ao desativar %s: %v", symbol, err)
 				}
@@ -101,18 +145,15 @@ func Main(minDate, maxDate string) {
 			}
 		}
 
-		if !disabledCryptos[symbol] {
+		if !disabledCryptos[symbol] && unverified > 0 {
+			log.Printf("⚠️ %s: %d dias não verificados, mantendo como está", symbol, unverified)
+		} else if !disabledCryptos[symbol] {
 			if err := enableCrypto(crypto.Symbol); err != nil {
 				log.Printf("❌ Erro ao ativar %s: %v", symbol, err)
 			} else {
 				log.Printf("✅ %s ativada", symbol)
 			}
 		}
-
-		// Aguardar um pouco entre as requisições para não sobrecarregar a API
-		if httpRequestMaked {
-			time.Sleep(500 * time.Millisecond)
-		}
 	}
 
 	log.Printf("✨ Verificação concluída!")
@@ -184,18 +225,18 @@ func enableCrypto(crypto string) error {
 }
 
 // Verificar se uma criptomoeda está disponível na Binance em uma data específica
-func checkCryptoAvailability(symbol, interval, date string, httpRequestMaked *bool) bool {
+func checkCryptoAvailability(downloads *ledger.Ledger, symbol, interval, date string) availability {
 	dateTime, err := time.Parse("2006-01-02", date)
 	if err != nil {
 		log.Printf("❌ Formato de data inválido: %v", err)
-		return false
+		return unknown
 	}
 
 	year := dateTime.Year()
 	month := dateTime.Month()
 	day := dateTime.Day()
 
-	baseURL := "https://data.binance.vision/data/spot/daily/klines"
+	baseURL := dataClient.URL("/data/spot/daily/klines")
 	monthStr := fmt.Sprintf("%02d", month)
 	dayStr := fmt.Sprintf("%02d", day)
 	fileName := fmt.Sprintf("%s-%s-%d-%s-%s", symbol, interval, year, monthStr, dayStr)
@@ -206,104 +247,52 @@ func checkCryptoAvailability(symbol, interval, date string, httpRequestMaked *bo
 
 	// Verificar se o arquivo CSV já existe
 	if _, err := os.Stat(csvFilePath); err == nil {
-		return true
+		return available
 	} else {
 		log.Printf("⚠️ Arquivo não encontrado %s", csvFilePath)
 	}
 
-	if isOfflineLink(url) {
+	if skip, err := downloads.Skip(url); err != nil {
+		log.Printf("⚠️ %v", err)
+	} else if skip {
 		log.Printf("❌ Link offline: %s", url)
-		return false
+		return notFound
 	}
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	// O cliente compartilhado espera antes de repetir após 429, 418, 5xx e erros de rede
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		log.Printf("⚠️ Erro ao verificar %s: %v", symbol, err)
+		return unknown
 	}
-
-	resp, err := client.Get(url)
-	*httpRequestMaked = true
+	resp, err := dataClient.Do(req)
 	if err != nil {
 		log.Printf("⚠️ Erro ao verificar %s: %v", symbol, err)
-		return false
+		if err := downloads.Record(url, 0, err, ""); err != nil {
+			log.Printf("⚠️ %v", err)
+		}
+		return unknown
 	}
 	defer resp.Body.Close()
 
+	// Só as falhas vão para o ledger: a verificação não baixa o arquivo, então
+	// um 200 aqui não pode contar como download no LedgerReport
+	if resp.StatusCode != http.StatusOK {
+		if err := downloads.Record(url, resp.StatusCode, fmt.Errorf("status %d", resp.StatusCode), ""); err != nil {
+			log.Printf("⚠️ %v", err)
+		}
+	}
+
 	if resp.StatusCode == http.StatusNotFound {
 		log.Printf("❌ 404 - Arquivo não encontrado: %s", fileName)
-		return false
+		return notFound
 	}
 
 	if resp.StatusCode == http.StatusOK {
 		log.Printf("✅ Disponível: %s", fileName)
-		return true
+		return available
 	}
 
 	log.Printf("⚠️ Status %d para %s", resp.StatusCode, fileName)
-	return false
-}
-
-func isOfflineLink(link string) bool {
-	offlineFile := os.Getenv("DATA_DIR") + "/offline_links.txt"
-	// Verifica se o diretório existe, se não, cria
-	if err := os.MkdirAll(filepath.Dir(offlineFile), 0755); err != nil {
-		log.Printf("Erro ao criar diretório para offline_links.txt: %v", err)
-		return false
-	}
-	// Tenta abrir o arquivo, se não existir, cria
-	if _, err := os.Stat(offlineFile); os.IsNotExist(err) {
-		file, err := os.Create(offlineFile)
-		if err != nil {
-			log.Printf("Erro ao criar offline_links.txt: %v", err)
-			return false
-		}
-		file.Close()
-	}
-
-	// Se o arquivo existir, verifica se alguma linha contém o link
-	content, err := os.ReadFile(offlineFile)
-	if err != nil {
-		log.Printf("Erro ao ler offline_links.txt: %v", err)
-		return false
-	}
-	lines := string(content)
-	if lines != "" {
-		for _, line := range splitLines(lines) {
-			if line != "" && contains(line, link) {
-				return true
-			}
-		}
-	}
-	return false
-}
-
-// splitLines divide uma string em linhas, suportando \n e \r\n
-func splitLines(s string) []string {
-	var lines []string
-	start := 0
-	for i := 0; i < len(s); i++ {
-		if s[i] == '\n' {
-			line := s[start:i]
-			if len(line) > 0 && line[len(line)-1] == '\r' {
-				line = line[:len(line)-1]
-			}
-			lines = append(lines, line)
-			start = i + 1
-		}
-	}
-	if start < len(s) {
-		lines = append(lines, s[start:])
-	}
-	return lines
-}
-
-// contains verifica se substr está contido em s
-func contains(s, substr string) bool {
-	return len(substr) > 0 && len(s) >= len(substr) && (s == substr || (len(s) > len(substr) && (len(s) > 0 && len(substr) > 0 && (len(s) >= len(substr) && (func() bool {
-		for i := 0; i <= len(s)-len(substr); i++ {
-			if s[i:i+len(substr)] == substr {
-				return true
-			}
-		}
-		return false
-	}())))))
+	return unknown
 }
@@ -0,0 +1,80 @@
+package generateDataset
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Políticas para candles ausentes de uma crypto em um OpenTime
+const (
+	// Repete o último fechamento conhecido com volume zero
+	GapForwardFill = "ffill"
+	// Descarta a linha inteira
+	GapDrop = "drop"
+	// Emite NaN nos valores da crypto e 1 na coluna <COIN>_missing
+	GapNaN = "nan"
+)
+
+var GapPolicies = []string{GapForwardFill, GapDrop, GapNaN}
+
+func isValidGapPolicy(policy string) bool {
+	for _, p := range GapPolicies {
+		if p == policy {
+			return true
+		}
+	}
+	return false
+}
+
+// Contagem de candles ausentes de uma crypto em um dia
+type gapStats struct {
+	Symbol       string
+	Expected     int
+	Present      int
+	Missing      int
+	Filled       int
+	FirstMissing int64
+}
+
+// forwardFill cria um candle sem negociação no preço de fechamento anterior
+func forwardFill(prev *klineRow, openTime, step int64) klineRow {
+	return klineRow{
+		OpenTime:  openTime,
+		CloseTime: openTime + step - 1,
+		Open:      prev.Close,
+		High:      prev.Close,
+		Low:       prev.Close,
+		Close:     prev.Close,
+	}
+}
+
+// writeGapReport grava o relatório de candles ausentes do dia
+func writeGapReport(path string, stats []*gapStats, droppedRows int) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("erro ao criar relatório de gaps: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"symbol", "expected", "present", "missing", "filled", "first_missing_open_time", "dropped_rows"})
+	for _, s := range stats {
+		firstMissing := ""
+		if s.Missing > 0 {
+			firstMissing = strconv.FormatInt(s.FirstMissing, 10)
+		}
+		writer.Write([]string{
+			s.Symbol,
+			strconv.Itoa(s.Expected),
+			strconv.Itoa(s.Present),
+			strconv.Itoa(s.Missing),
+			strconv.Itoa(s.Filled),
+			firstMissing,
+			strconv.Itoa(droppedRows),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,56 @@
+package generateDataset
+
+import (
+	"app/src/utils"
+	"log"
+	"runtime"
+)
+
+// Bytes aproximados do estado dos indicadores por candle de warmup
+const featureStateBytes = 16
+
+// dayMemory estima a memória usada pela geração de um dia: um buffer de
+// leitura e o estado de cada crypto, a linha de saída e o buffer do writer
+// (o parquet mantém o row group do dia inteiro até o fim do dia).
+func dayMemory(coins, columns, warmup int, config Config, readBuffer int) int64 {
+	perCoin := int64(readBuffer) + 2*klineRowBytes + int64(warmup)*featureStateBytes
+	// Buffer circular do aquecimento, usado por uma crypto de cada vez
+	memory := int64(coins)*perCoin + int64(warmup)*klineRowBytes + int64(columns)*8
+
+	if config.Format == FormatParquet {
+		rows := int64(utils.RowsPerDay(config.Interval))
+		memory += rows*int64(columns)*8 + parquetPageBytes + int64(parquetBatchRows(columns)*columns*parquetValueBytes)
+	} else {
+		memory += 64 * 1024
+	}
+	return memory
+}
+
+// planWorkers decide quantos dias são gerados em paralelo e o buffer de
+// leitura de cada arquivo de klines dentro de config.MemoryMB. A quantidade de
+// cryptos afeta apenas a memória de cada dia: com muitas cryptos o buffer de
+// leitura diminui primeiro e depois a quantidade de workers.
+func planWorkers(config Config, coins, columns, warmup int) (workers, readBuffer int) {
+	workers = config.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	budget := int64(config.MemoryMB) * 1024 * 1024
+
+	readBuffer = maxReadBuffer
+	for readBuffer > minReadBuffer && dayMemory(coins, columns, warmup, config, readBuffer)*int64(workers) > budget {
+		readBuffer /= 2
+	}
+
+	perDay := dayMemory(coins, columns, warmup, config, readBuffer)
+	if fit := int(budget / perDay); fit < workers {
+		workers = max(fit, 1)
+	}
+	if perDay > budget {
+		log.Printf("⚠️ Cada dia usa cerca de %d MB, acima do limite de %d MB: gerando um dia por vez", perDay/(1024*1024), config.MemoryMB)
+	}
+
+	log.Printf("🧮 %d cryptos, %d colunas: %d workers, buffer de leitura de %d KB (~%d MB por dia, limite %d MB)",
+		coins, columns, workers, readBuffer/1024, perDay/(1024*1024), config.MemoryMB)
+	return workers, readBuffer
+}
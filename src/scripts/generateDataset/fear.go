@@ -0,0 +1,120 @@
+package generateDataset
+
+import (
+	"app/src/features"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Políticas para linhas sem valor de algum índice de medo
+const (
+	// Descarta a linha (e o dia inteiro quando o índice não cobre o dia)
+	FearMissingSkip = "skip"
+	// Mantém a linha com NaN na coluna do índice
+	FearMissingNaN = "nan"
+)
+
+var FearMissingPolicies = []string{FearMissingSkip, FearMissingNaN}
+
+func isValidFearMissing(policy string) bool {
+	for _, p := range FearMissingPolicies {
+		if p == policy {
+			return true
+		}
+	}
+	return false
+}
+
+type fearPoint struct {
+	Time  int64
+	Value float64
+}
+
+// fearSeries são os valores publicados de um índice, em ordem cronológica
+type fearSeries struct {
+	Source string
+	Target string
+	points []fearPoint
+}
+
+// loadFearSeries carrega o histórico de uma fonte para target (o símbolo da
+// crypto, ou vazio para o índice de mercado). As datas são gravadas em UTC
+// pelos importadores.
+func loadFearSeries(db *sql.DB, source, target string) (*fearSeries, error) {
+	rows, err := db.Query(`
+        SELECT date, value
+        FROM fear_index
+        WHERE source = ? AND COALESCE(target, '') = ?
+        ORDER BY date;
+    `, source, target)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	series := &fearSeries{Source: source, Target: target}
+	for rows.Next() {
+		var date time.Time
+		var value float64
+		if err := rows.Scan(&date, &value); err != nil {
+			return nil, err
+		}
+		utc := time.Date(date.Year(), date.Month(), date.Day(), date.Hour(), date.Minute(), date.Second(), 0, time.UTC)
+		series.points = append(series.points, fearPoint{Time: utc.UnixMilli(), Value: value})
+	}
+	return series, rows.Err()
+}
+
+// latest retorna a posição do último valor publicado até t, ou -1
+func (s *fearSeries) latest(t int64) int {
+	return sort.Search(len(s.points), func(i int) bool { return s.points[i].Time > t }) - 1
+}
+
+// at é o valor do índice no OpenTime t (as-of): o último valor publicado até
+// t, ou NaN se não houver ou se for mais antigo que maxStaleness (0 desativa).
+//
+// Com interpolate o valor vai do penúltimo ao último ponto publicado ao longo
+// do período seguinte à publicação, então só usa valores já conhecidos em t.
+func (s *fearSeries) at(t int64, interpolate bool, maxStaleness time.Duration) float64 {
+	i := s.latest(t)
+	if i < 0 {
+		return math.NaN()
+	}
+	last := s.points[i]
+	if maxStaleness > 0 && t-last.Time > maxStaleness.Milliseconds() {
+		return math.NaN()
+	}
+	if !interpolate || i == 0 {
+		return last.Value
+	}
+
+	previous := s.points[i-1]
+	fraction := math.Min(float64(t-last.Time)/float64(last.Time-previous.Time), 1)
+	return previous.Value + (last.Value-previous.Value)*fraction
+}
+
+// covers indica se o índice tem algum valor em [from, to)
+func (s *fearSeries) covers(from, to int64, interpolate bool, maxStaleness time.Duration) bool {
+	if !math.IsNaN(s.at(from, interpolate, maxStaleness)) {
+		return true
+	}
+	i := s.latest(from) + 1
+	return i < len(s.points) && s.points[i].Time < to
+}
+
+// describe lista os pontos que influenciam [from, to), usado no manifesto do dia
+func (s *fearSeries) describe(from, to int64) string {
+	first := max(s.latest(from)-1, 0)
+	var parts []string
+	for _, p := range s.points[first:] {
+		if p.Time >= to {
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s", time.UnixMilli(p.Time).UTC().Format(time.RFC3339), features.Format(p.Value)))
+	}
+	return strings.Join(parts, ",")
+}
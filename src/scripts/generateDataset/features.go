@@ -0,0 +1,96 @@
+package generateDataset
+
+import (
+	"app/src/features"
+	"app/src/utils"
+	"os"
+	"time"
+)
+
+// coinState é o estado de uma crypto que atravessa os dias: os indicadores
+// (nil sem spec de features) e o último kline conhecido, usado no forward-fill.
+type coinState struct {
+	features *features.Set
+	last     klineRow
+	hasLast  bool
+}
+
+// newCoinStates cria o estado de cada crypto e o aquece com os klines dos dias
+// anteriores, para que indicadores e forward-fill não reiniciem a cada dia. As
+// cryptos são aquecidas uma de cada vez, lendo os arquivos em streaming.
+func newCoinStates(specs []features.Spec, cryptos []string, klineBasePath string, config Config, currentTime time.Time, bufferSize int) ([]*coinState, error) {
+	states := make([]*coinState, len(cryptos))
+	for c, crypto := range cryptos {
+		state := &coinState{}
+		if len(specs) > 0 {
+			set, err := features.NewSet(specs)
+			if err != nil {
+				return nil, err
+			}
+			state.features = set
+		}
+		if err := state.warmUp(crypto, klineBasePath, config, currentTime, bufferSize); err != nil {
+			return nil, err
+		}
+		states[c] = state
+	}
+	return states, nil
+}
+
+// warmUp alimenta os indicadores com os últimos Warmup() klines anteriores ao
+// dia atual e guarda o último kline do dia anterior. Dias sem arquivo encerram a busca.
+func (s *coinState) warmUp(crypto, klineBasePath string, config Config, currentTime time.Time, bufferSize int) error {
+	count := 0
+	if s.features != nil {
+		count = s.features.Warmup()
+	}
+	needLast := config.GapPolicy == GapForwardFill
+	if count == 0 && !needLast {
+		return nil
+	}
+
+	// Dias anteriores necessários, do mais recente para o mais antigo
+	cryptoPair := crypto + "USDT"
+	rowsPerDay := utils.RowsPerDay(config.Interval)
+	days := max((count+rowsPerDay-1)/rowsPerDay, 1)
+	var paths []string
+	for d := 1; d <= days; d++ {
+		path := klineFilePath(klineBasePath, cryptoPair, config.Interval, currentTime.AddDate(0, 0, -d))
+		if _, err := os.Stat(path); err != nil {
+			break
+		}
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	// Os últimos count klines ficam em um buffer circular
+	ring := make([]klineRow, count)
+	total := 0
+	for i := len(paths) - 1; i >= 0; i-- {
+		err := streamKlineFile(paths[i], bufferSize, func(k *klineRow) {
+			if count > 0 {
+				ring[total%count] = *k
+			}
+			total++
+			if i == 0 {
+				s.last = *k
+				s.hasLast = true
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	first := max(total-count, 0)
+	for i := first; i < total; i++ {
+		k := ring[i%count]
+		s.features.Update(k.candle(crypto))
+	}
+	if !needLast {
+		s.hasLast = false
+	}
+	return nil
+}
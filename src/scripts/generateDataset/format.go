@@ -0,0 +1,444 @@
+package generateDataset
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"github.com/parquet-go/parquet-go/encoding"
+)
+
+// Formatos de arquivo do dataset
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+var Formats = []string{FormatCSV, FormatParquet}
+
+// Compressões aceitas no formato parquet
+const (
+	CompressionSnappy = "snappy"
+	CompressionZstd   = "zstd"
+)
+
+var Compressions = []string{CompressionSnappy, CompressionZstd}
+
+func isValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidCompression(compression string) bool {
+	for _, c := range Compressions {
+		if c == compression {
+			return true
+		}
+	}
+	return false
+}
+
+// datasetWriter grava as linhas do dataset, um valor por coluna do cabeçalho.
+// Colunas inteiras (isIntColumn) também são passadas como float64 e NaN indica
+// valor ausente.
+type datasetWriter interface {
+	Write(values []float64) error
+	Close() error
+}
+
+// datasetReader lê as linhas do dataset já convertidas. Read retorna io.EOF no fim.
+type datasetReader interface {
+	Header() []string
+	Read() ([]float64, error)
+	Close() error
+}
+
+// newDatasetWriter cria o arquivo em path no formato do config e grava o cabeçalho
+func newDatasetWriter(path string, header []string, config Config) (datasetWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Format == FormatParquet {
+		return newParquetDatasetWriter(file, header, config), nil
+	}
+
+	writer := &csvDatasetWriter{file: file, writer: bufio.NewWriter(file), isInt: intColumns(header)}
+	if _, err := writer.writer.WriteString(strings.Join(header, ",") + "\n"); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return writer, nil
+}
+
+// openDatasetReader abre um dataset gravado por newDatasetWriter
+func openDatasetReader(path, format string) (datasetReader, error) {
+	if format == FormatParquet {
+		return openParquetDatasetReader(path)
+	}
+	return openCSVDatasetReader(path)
+}
+
+type csvDatasetWriter struct {
+	file   *os.File
+	writer *bufio.Writer
+	isInt  []bool
+	line   []byte
+}
+
+func (w *csvDatasetWriter) Write(values []float64) error {
+	line := w.line[:0]
+	for i, value := range values {
+		if i > 0 {
+			line = append(line, ',')
+		}
+		switch {
+		case math.IsNaN(value) || math.IsInf(value, 0):
+			line = append(line, "NaN"...)
+		case w.isInt[i]:
+			line = strconv.AppendInt(line, int64(value), 10)
+		default:
+			line = strconv.AppendFloat(line, value, 'f', -1, 64)
+		}
+	}
+	w.line = append(line, '\n')
+	_, err := w.writer.Write(w.line)
+	return err
+}
+
+func (w *csvDatasetWriter) Close() error {
+	if err := w.writer.Flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+type csvDatasetReader struct {
+	file    *os.File
+	scanner *bufio.Scanner
+	header  []string
+}
+
+func openCSVDatasetReader(path string) (*csvDatasetReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	if !scanner.Scan() {
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s está vazio", path)
+	}
+
+	return &csvDatasetReader{
+		file:    file,
+		scanner: scanner,
+		header:  strings.Split(scanner.Text(), ","),
+	}, nil
+}
+
+func (r *csvDatasetReader) Header() []string { return r.header }
+
+func (r *csvDatasetReader) Read() ([]float64, error) {
+	for r.scanner.Scan() {
+		fields := strings.Split(r.scanner.Text(), ",")
+		if len(fields) != len(r.header) {
+			// Linha incompleta
+			continue
+		}
+		values := make([]float64, len(fields))
+		for i, field := range fields {
+			values[i] = parseFloatOrNaN(field)
+		}
+		return values, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func (r *csvDatasetReader) Close() error { return r.file.Close() }
+
+// isIntColumn indica as colunas gravadas como int64 no parquet. As demais são float64.
+func isIntColumn(name string) bool {
+	return name == "OpenTime" ||
+		strings.HasSuffix(name, "_NumberOfTrades") ||
+		strings.HasSuffix(name, "_missing") ||
+		strings.Contains(name, "_label_barrier_")
+}
+
+func intColumns(header []string) []bool {
+	isInt := make([]bool, len(header))
+	for i, name := range header {
+		isInt[i] = isIntColumn(name)
+	}
+	return isInt
+}
+
+// parquetDatasetWriter grava as colunas tipadas (OpenTime obrigatório, inteiros
+// anuláveis para representar NaN) e inicia um novo row group a cada dia UTC.
+type parquetDatasetWriter struct {
+	file     *os.File
+	writer   *parquet.Writer
+	isInt    []bool
+	day      int64
+	rows     []parquet.Row
+	batch    int
+	firstRow bool
+}
+
+// Linhas acumuladas antes de cada escrita (ou lidas de uma vez) no parquet,
+// limitadas para que datasets com centenas de cryptos não ocupem parquetBatchBytes
+const (
+	parquetBatchSize  = 1024
+	parquetBatchBytes = 4 * 1024 * 1024
+)
+
+// parquetValueBytes é o tamanho aproximado de um parquet.Value em memória
+const parquetValueBytes = 24
+
+// parquetBatchRows é a quantidade de linhas por lote para a quantidade de colunas
+func parquetBatchRows(columns int) int {
+	rows := parquetBatchBytes / (parquetValueBytes * max(columns, 1))
+	return min(max(rows, 1), parquetBatchSize)
+}
+
+// Memória total dos buffers de página do writer parquet, dividida entre as colunas
+const parquetPageBytes = 16 * 1024 * 1024
+
+// parquetPageBufferSize é o buffer de página (e o bloco das páginas já
+// codificadas) de cada coluna. O padrão do parquet-go, 256 KB por coluna,
+// passaria de 1 GB com centenas de cryptos.
+func parquetPageBufferSize(columns int) int {
+	size := parquetPageBytes / max(columns, 1)
+	return min(max(size, 8*1024), parquet.DefaultPageBufferSize)
+}
+
+func newParquetDatasetWriter(file *os.File, header []string, config Config) *parquetDatasetWriter {
+	var codec compress.Codec = &parquet.Snappy
+	if config.Compression == CompressionZstd {
+		codec = &parquet.Zstd
+	}
+
+	isInt := intColumns(header)
+	nodes := make([]parquet.Node, len(header))
+	for i, name := range header {
+		switch {
+		case name == "OpenTime":
+			nodes[i] = parquet.Int(64)
+		case isInt[i]:
+			nodes[i] = parquet.Optional(parquet.Int(64))
+		default:
+			nodes[i] = parquet.Leaf(parquet.DoubleType)
+		}
+	}
+
+	schema := parquet.NewSchema("dataset", &orderedGroup{names: header, nodes: nodes})
+	pageBuffer := parquetPageBufferSize(len(header))
+	return &parquetDatasetWriter{
+		file: file,
+		writer: parquet.NewWriter(file, schema,
+			parquet.Compression(codec),
+			parquet.PageBufferSize(pageBuffer),
+			parquet.ColumnPageBuffers(parquet.NewChunkBufferPool(pageBuffer)),
+		),
+		isInt:    isInt,
+		rows:     make([]parquet.Row, 0, parquetBatchRows(len(header))),
+		batch:    parquetBatchRows(len(header)),
+		firstRow: true,
+	}
+}
+
+func (w *parquetDatasetWriter) Write(values []float64) error {
+	day := int64(values[0]) / int64(24*time.Hour/time.Millisecond)
+	if !w.firstRow && day != w.day {
+		// Um row group por dia
+		if err := w.flush(); err != nil {
+			return err
+		}
+		if err := w.writer.Flush(); err != nil {
+			return err
+		}
+	}
+	w.day = day
+	w.firstRow = false
+
+	// As linhas do lote são reaproveitadas depois de cada flush
+	n := len(w.rows)
+	w.rows = w.rows[:n+1]
+	if w.rows[n] == nil {
+		w.rows[n] = make(parquet.Row, len(values))
+	}
+	for i, value := range values {
+		w.rows[n][i] = w.value(i, value)
+	}
+	if len(w.rows) >= w.batch {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *parquetDatasetWriter) value(column int, value float64) parquet.Value {
+	if !w.isInt[column] {
+		return parquet.ValueOf(value).Level(0, 0, column)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return parquet.NullValue().Level(0, 0, column)
+	}
+	if column == 0 {
+		return parquet.ValueOf(int64(value)).Level(0, 0, column)
+	}
+	return parquet.ValueOf(int64(value)).Level(0, 1, column)
+}
+
+func (w *parquetDatasetWriter) flush() error {
+	if len(w.rows) == 0 {
+		return nil
+	}
+	_, err := w.writer.WriteRows(w.rows)
+	w.rows = w.rows[:0]
+	return err
+}
+
+func (w *parquetDatasetWriter) Close() error {
+	if err := w.flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+	if err := w.writer.Close(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+type parquetDatasetReader struct {
+	file   *os.File
+	reader *parquet.Reader
+	header []string
+	rows   []parquet.Row
+	next   int
+	count  int
+}
+
+func openParquetDatasetReader(path string) (*parquetDatasetReader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	parquetFile, err := parquet.OpenFile(file, stat.Size())
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("erro ao abrir parquet %s: %w", path, err)
+	}
+
+	var header []string
+	for _, field := range parquetFile.Schema().Fields() {
+		header = append(header, field.Name())
+	}
+
+	return &parquetDatasetReader{
+		file:   file,
+		reader: parquet.NewReader(parquetFile),
+		header: header,
+		rows:   make([]parquet.Row, parquetBatchRows(len(header))),
+	}, nil
+}
+
+func (r *parquetDatasetReader) Header() []string { return r.header }
+
+func (r *parquetDatasetReader) Read() ([]float64, error) {
+	if r.next >= r.count {
+		n, err := r.reader.ReadRows(r.rows)
+		if n == 0 {
+			if err == nil {
+				err = io.EOF
+			}
+			return nil, err
+		}
+		r.next, r.count = 0, n
+	}
+
+	row := r.rows[r.next]
+	r.next++
+
+	values := make([]float64, len(r.header))
+	for _, value := range row {
+		column := value.Column()
+		switch {
+		case value.IsNull():
+			values[column] = math.NaN()
+		case value.Kind() == parquet.Int64:
+			values[column] = float64(value.Int64())
+		default:
+			values[column] = value.Double()
+		}
+	}
+	return values, nil
+}
+
+func (r *parquetDatasetReader) Close() error {
+	r.reader.Close()
+	return r.file.Close()
+}
+
+// orderedGroup é um grupo parquet que mantém as colunas na ordem do cabeçalho
+// (parquet.Group ordena os campos alfabeticamente).
+type orderedGroup struct {
+	names []string
+	nodes []parquet.Node
+}
+
+func (g *orderedGroup) ID() int                     { return 0 }
+func (g *orderedGroup) String() string              { return parquet.Group{}.String() }
+func (g *orderedGroup) Type() parquet.Type          { return parquet.Group{}.Type() }
+func (g *orderedGroup) Optional() bool              { return false }
+func (g *orderedGroup) Repeated() bool              { return false }
+func (g *orderedGroup) Required() bool              { return true }
+func (g *orderedGroup) Leaf() bool                  { return false }
+func (g *orderedGroup) Encoding() encoding.Encoding { return nil }
+func (g *orderedGroup) Compression() compress.Codec { return nil }
+func (g *orderedGroup) GoType() reflect.Type        { return reflect.TypeOf(map[string]any{}) }
+func (g *orderedGroup) Fields() []parquet.Field {
+	fields := make([]parquet.Field, len(g.names))
+	for i := range g.names {
+		fields[i] = orderedField{Node: g.nodes[i], name: g.names[i]}
+	}
+	return fields
+}
+
+type orderedField struct {
+	parquet.Node
+	name string
+}
+
+func (f orderedField) Name() string { return f.name }
+
+func (f orderedField) Value(base reflect.Value) reflect.Value {
+	return base.MapIndex(reflect.ValueOf(f.name))
+}
@@ -0,0 +1,162 @@
+package generateDataset
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Colunas OHLC de uma crypto no dataset_full.csv
+type coinColumns struct {
+	Symbol string
+	Open   int
+	High   int
+	Low    int
+	Close  int
+}
+
+// Linha do dataset_full.csv com os preços de cada crypto separados
+type horizonRow struct {
+	OpenTime int64
+	Fields   []float64
+	Open     []float64
+	High     []float64
+	Low      []float64
+	Close    []float64
+}
+
+// datasetColumns localiza as colunas de cada crypto e as colunas de medo no cabeçalho
+func datasetColumns(header []string) ([]coinColumns, []int, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[name] = i
+	}
+
+	var coins []coinColumns
+	var fearColumns []int
+	for i, name := range header {
+		if strings.HasPrefix(name, "fear_") {
+			fearColumns = append(fearColumns, i)
+			continue
+		}
+		symbol, ok := strings.CutSuffix(name, "_Close")
+		if !ok {
+			continue
+		}
+		coin := coinColumns{Symbol: symbol, Close: i}
+		var found bool
+		if coin.Open, found = index[symbol+"_Open"]; !found {
+			return nil, nil, fmt.Errorf("coluna %s_Open não encontrada", symbol)
+		}
+		if coin.High, found = index[symbol+"_High"]; !found {
+			return nil, nil, fmt.Errorf("coluna %s_High não encontrada", symbol)
+		}
+		if coin.Low, found = index[symbol+"_Low"]; !found {
+			return nil, nil, fmt.Errorf("coluna %s_Low não encontrada", symbol)
+		}
+		coins = append(coins, coin)
+	}
+	if len(coins) == 0 {
+		return nil, nil, fmt.Errorf("nenhuma coluna _Close encontrada no dataset")
+	}
+	return coins, fearColumns, nil
+}
+
+func newHorizonRow(fields []float64, coins []coinColumns) *horizonRow {
+	row := &horizonRow{
+		OpenTime: int64(fields[0]),
+		Fields:   fields,
+		Open:     make([]float64, len(coins)),
+		High:     make([]float64, len(coins)),
+		Low:      make([]float64, len(coins)),
+		Close:    make([]float64, len(coins)),
+	}
+	for c, coin := range coins {
+		row.Open[c] = fields[coin.Open]
+		row.High[c] = fields[coin.High]
+		row.Low[c] = fields[coin.Low]
+		row.Close[c] = fields[coin.Close]
+	}
+	return row
+}
+
+// streamHorizon lê as linhas do reader e chama emit para cada linha
+// junto com as horizon linhas seguintes. Linhas cujo horizonte não é formado
+// por candles consecutivos (gap no meio ou fim do período) não são emitidas e
+// entram na contagem skipped.
+func streamHorizon(reader datasetReader, coins []coinColumns, horizon int, step int64, emit func(row *horizonRow, future []*horizonRow) error) (written, skipped int, err error) {
+	// Janela com a linha atual e os horizon candles seguintes
+	window := make([]*horizonRow, 0, horizon+1)
+
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return written, skipped, err
+		}
+		window = append(window, newHorizonRow(fields, coins))
+
+		if len(window) < horizon+1 {
+			continue
+		}
+
+		current := window[0]
+		last := window[horizon]
+		// Com a política drop ou dias ausentes os candles seguintes podem não ser consecutivos
+		if last.OpenTime-current.OpenTime == int64(horizon)*step {
+			if err := emit(current, window[1:]); err != nil {
+				return written, skipped, err
+			}
+			written++
+		} else {
+			skipped++
+		}
+		window = window[1:]
+	}
+
+	// As últimas linhas não têm candles futuros suficientes
+	skipped += len(window)
+	return written, skipped, nil
+}
+
+func headerNames(header []string, columns []int) []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = header[column]
+	}
+	return names
+}
+
+func parseFloatOrNaN(value string) float64 {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return math.NaN()
+	}
+	return parsed
+}
+
+// nanMax e nanMin propagam NaN: um candle ausente no horizonte invalida o alvo
+func nanMax(a, b float64) float64 {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.NaN()
+	}
+	return math.Max(a, b)
+}
+
+func nanMin(a, b float64) float64 {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.NaN()
+	}
+	return math.Min(a, b)
+}
+
+func percentOf(price, reference float64) float64 {
+	if reference == 0 {
+		return math.NaN()
+	}
+	return (price - reference) / reference * 100
+}
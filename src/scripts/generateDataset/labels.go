@@ -0,0 +1,123 @@
+package generateDataset
+
+import (
+	"app/src/utils"
+	"log"
+	"math"
+	"os"
+	"strconv"
+)
+
+// Classes do rótulo triple-barrier
+const (
+	BarrierUp      = 1
+	BarrierTimeout = 0
+	BarrierDown    = -1
+)
+
+// addLabels acrescenta ao dataset_full as colunas de rótulo de cada crypto,
+// todas calculadas sobre os próximos LabelHorizon candles a partir do Close da linha:
+//
+//	<COIN>_label_return_<N>   retorno percentual até o Close do candle N
+//	<COIN>_label_max_high_<N> variação percentual até o maior High do horizonte
+//	<COIN>_label_min_low_<N>  variação percentual até o menor Low do horizonte
+//	<COIN>_label_barrier_<N>  1 se +LabelUpper% for atingido primeiro, -1 se -LabelLower%, 0 se nenhum
+//
+// As linhas sem o horizonte completo são removidas, para que nenhum rótulo use
+// dados fora do período (look-ahead).
+func addLabels(config Config) ([]string, int, error) {
+	fullPath := finalDatasetPath("dataset_full", config.Format)
+	tempPath := fullPath + ".tmp"
+
+	reader, err := openDatasetReader(fullPath, config.Format)
+	if err != nil {
+		return nil, 0, err
+	}
+	header := reader.Header()
+
+	coins, _, err := datasetColumns(header)
+	if err != nil {
+		reader.Close()
+		return nil, 0, err
+	}
+
+	horizon := config.LabelHorizon
+	suffix := "_" + strconv.Itoa(horizon)
+	labelHeader := append([]string{}, header...)
+	for _, coin := range coins {
+		labelHeader = append(labelHeader,
+			coin.Symbol+"_label_return"+suffix,
+			coin.Symbol+"_label_max_high"+suffix,
+			coin.Symbol+"_label_min_low"+suffix,
+			coin.Symbol+"_label_barrier"+suffix,
+		)
+	}
+	writer, err := newDatasetWriter(tempPath, labelHeader, config)
+	if err != nil {
+		reader.Close()
+		return nil, 0, err
+	}
+
+	intervalDuration, _ := utils.IntervalDuration(config.Interval)
+
+	written, skipped, err := streamHorizon(reader, coins, horizon, intervalDuration.Milliseconds(), func(row *horizonRow, future []*horizonRow) error {
+		line := row.Fields
+		for c := range coins {
+			reference := row.Close[c]
+			high, low := math.Inf(-1), math.Inf(1)
+			for _, f := range future {
+				high = nanMax(high, f.High[c])
+				low = nanMin(low, f.Low[c])
+			}
+			line = append(line,
+				percentOf(future[len(future)-1].Close[c], reference),
+				percentOf(high, reference),
+				percentOf(low, reference),
+				tripleBarrier(reference, future, c, config.LabelUpper, config.LabelLower),
+			)
+		}
+		return writer.Write(line)
+	})
+	// O dataset_full é substituído, então o reader precisa ser fechado antes do rename
+	reader.Close()
+	if err != nil {
+		writer.Close()
+		return nil, 0, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := os.Rename(tempPath, fullPath); err != nil {
+		return nil, 0, err
+	}
+
+	log.Printf("🏷️ Rótulos com horizonte %d adicionados a %s: %d linhas, %d removidas por falta de horizonte",
+		horizon, fullPath, written, skipped)
+	return labelHeader, written, nil
+}
+
+// tripleBarrier percorre o horizonte em ordem e retorna a primeira barreira
+// atingida. Se as duas forem atingidas no mesmo candle não é possível saber a
+// ordem e o resultado é BarrierDown, o caso conservador.
+func tripleBarrier(reference float64, future []*horizonRow, coin int, upper, lower float64) float64 {
+	if math.IsNaN(reference) || reference == 0 {
+		return math.NaN()
+	}
+	upperPrice := reference * (1 + upper/100)
+	lowerPrice := reference * (1 - lower/100)
+
+	for _, f := range future {
+		high, low := f.High[coin], f.Low[coin]
+		if math.IsNaN(high) || math.IsNaN(low) {
+			return math.NaN()
+		}
+		if low <= lowerPrice {
+			return BarrierDown
+		}
+		if high >= upperPrice {
+			return BarrierUp
+		}
+	}
+	return BarrierTimeout
+}
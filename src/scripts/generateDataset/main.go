@@ -2,20 +2,165 @@ package generateDataset
 
 import (
 	"app/src/database"
-	"app/src/models"
-	"bufio"
+	"app/src/features"
+	"app/src/sentiment"
+	"app/src/utils"
 	"database/sql"
+	"fmt"
+	"io"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
-	"runtime"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 )
 
-func Main(initialDate time.Time, endDate time.Time, clearFiles bool) {
+// Config define o período e o formato do dataset gerado
+type Config struct {
+	Start      time.Time
+	End        time.Time
+	ClearFiles bool
+	Interval   string
+	GapPolicy  string
+
+	// Formato dos arquivos gerados (csv ou parquet) e compressão do parquet
+	Format      string
+	Compression string
+
+	// Alvos do dataset_percent.csv
+	PercentReference string
+	PercentHorizon   int
+
+	// Rótulos supervisionados (desativados com LabelHorizon 0) e barreiras
+	// percentuais do triple-barrier
+	LabelHorizon int
+	LabelUpper   float64
+	LabelLower   float64
+
+	// Spec dos indicadores técnicos por crypto (ex: "sma:20,rsi:14,macd:12:26:9")
+	Features string
+
+	// Índices de medo: valor as-of (último publicado até o OpenTime da linha),
+	// interpolação opcional entre os pontos diários, idade máxima do valor e
+	// política para linhas sem valor de algum índice
+	FearInterpolate  bool
+	FearMaxStaleness time.Duration
+	FearMissing      string
+
+	// Sentimento por crypto (<COIN>_sentiment): fonte das séries com target =
+	// símbolo no fear_index (vazio desativa) e índice de mercado usado nas
+	// cryptos sem valor próprio (vazio deixa NaN). Segue o alinhamento as-of
+	// dos índices de medo.
+	Sentiment         string
+	SentimentFallback string
+
+	// Divisão cronológica exportada em DATASET_DIR/splits: proporções de
+	// treino/validação/teste (ex: "0.7,0.15,0.15") ou datas de início da
+	// validação e do teste (ex: "2024-06-01,2024-09-01"), candles de embargo
+	// antes de cada fronteira e quantidade de folds de walk-forward
+	SplitRatios string
+	SplitDates  string
+	Embargo     int
+	Folds       int
+
+	// Dias gerados em paralelo (0 usa a quantidade de CPUs) e limite de memória
+	// em MB. Os workers são reduzidos quando muitas cryptos não cabem no limite.
+	Workers  int
+	MemoryMB int
+}
+
+// DefaultConfig gera o dataset com klines de 1 minuto, preenchendo gaps com o último fechamento
+func DefaultConfig() Config {
+	return Config{
+		Interval:  "1m",
+		GapPolicy: GapForwardFill,
+
+		Format:      FormatCSV,
+		Compression: CompressionSnappy,
+
+		PercentReference: PercentReferenceClose,
+		PercentHorizon:   1,
+
+		LabelUpper: 1,
+		LabelLower: 1,
+
+		FearMaxStaleness: 48 * time.Hour,
+		FearMissing:      FearMissingSkip,
+
+		Sentiment:         sentiment.LocalSource,
+		SentimentFallback: "api.alternative.me",
+
+		MemoryMB: 1024,
+	}
+}
+
+func Main(config Config) {
+	initialDate, endDate := config.Start, config.End
+
+	if !utils.IsValidInterval(config.Interval) {
+		log.Printf("❌ Intervalo inválido: %s", config.Interval)
+		return
+	}
+	if !isValidGapPolicy(config.GapPolicy) {
+		log.Printf("❌ Política de gaps inválida: %s (use %s)", config.GapPolicy, strings.Join(GapPolicies, ", "))
+		return
+	}
+	if !isValidFormat(config.Format) {
+		log.Printf("❌ Formato inválido: %s (use %s)", config.Format, strings.Join(Formats, ", "))
+		return
+	}
+	if config.Format == FormatParquet && !isValidCompression(config.Compression) {
+		log.Printf("❌ Compressão inválida: %s (use %s)", config.Compression, strings.Join(Compressions, ", "))
+		return
+	}
+	if !isValidPercentReference(config.PercentReference) {
+		log.Printf("❌ Referência percentual inválida: %s (use %s)", config.PercentReference, strings.Join(PercentReferences, ", "))
+		return
+	}
+	if config.PercentHorizon < 0 {
+		log.Printf("❌ Horizonte percentual inválido: %d", config.PercentHorizon)
+		return
+	}
+	if config.LabelHorizon < 0 {
+		log.Printf("❌ Horizonte dos rótulos inválido: %d", config.LabelHorizon)
+		return
+	}
+	if config.LabelHorizon > 0 && (config.LabelUpper <= 0 || config.LabelLower <= 0) {
+		log.Printf("❌ Barreiras dos rótulos devem ser positivas: +%v%% / -%v%%", config.LabelUpper, config.LabelLower)
+		return
+	}
+	if !isValidFearMissing(config.FearMissing) {
+		log.Printf("❌ Política de índices de medo ausentes inválida: %s (use %s)", config.FearMissing, strings.Join(FearMissingPolicies, ", "))
+		return
+	}
+	if config.MemoryMB <= 0 || config.Workers < 0 {
+		log.Printf("❌ Limite de memória (%d MB) e workers (%d) inválidos", config.MemoryMB, config.Workers)
+		return
+	}
+	featureSpecs, err := features.ParseSpec(config.Features)
+	if err != nil {
+		log.Printf("❌ Spec de features inválida: %v", err)
+		return
+	}
+	template, err := features.NewSet(featureSpecs)
+	if err != nil {
+		log.Printf("❌ Spec de features inválida: %v", err)
+		return
+	}
+
+	split, err := parseSplit(config)
+	if err != nil {
+		log.Printf("❌ Divisão inválida: %v", err)
+		return
+	}
+	if split != nil && config.Embargo < max(config.LabelHorizon, config.PercentHorizon) {
+		log.Printf("⚠️ Embargo de %d candles menor que o horizonte dos alvos: o fim de cada parte usa preços da parte seguinte", config.Embargo)
+	}
+
 	// Conexão com o banco de dados
 	db, err := database.ConnectDatabase()
 	if err != nil {
@@ -29,10 +174,32 @@ func Main(initialDate time.Time, endDate time.Time, clearFiles bool) {
 		panic(err)
 	}
 
+	// Histórico dos índices de medo registrados, carregado uma única vez
+	var fear []*fearSeries
+	for _, source := range sentiment.Sources() {
+		series, err := loadFearSeries(db, source.Name(), "")
+		if err != nil {
+			log.Printf("Erro ao carregar o índice de medo %s: %v", source.Name(), err)
+			return
+		}
+		fear = append(fear, series)
+	}
+
+	// Sentimento por crypto, emitido apenas quando a fonte tem séries por crypto
+	perCoin, err := loadCoinSentiment(db, config, cryptos)
+	if err != nil {
+		log.Printf("Erro ao carregar o sentimento por crypto de %s: %v", config.Sentiment, err)
+		return
+	}
+
 	// Gera dataset para cada dia entre a data inicial e a data final
+	columns := len(buildDatasetHeader(cryptos, config, template.Columns(), perCoin != nil))
+	workers, readBuffer := planWorkers(config, len(cryptos), columns, template.Warmup())
 	var wg sync.WaitGroup
-	maxGoroutines := runtime.NumCPU() * 2
-	sem := make(chan struct{}, maxGoroutines)
+	sem := make(chan struct{}, workers)
+	// Dias gerados (ou reaproveitados do cache) que entram no dataset final
+	var generatedMu sync.Mutex
+	generated := make(map[string]bool)
 	for i := initialDate; i.Before(time.Now().UTC()) && (i.Before(endDate) || i.Equal(endDate)); i = i.Add(24 * time.Hour) {
 		yearStr := fixedCases(i.Year())
 		monthStr := fixedCases(int(i.Month()))
@@ -40,49 +207,123 @@ func Main(initialDate time.Time, endDate time.Time, clearFiles bool) {
 
 		// Gera a data no formato YYYY-MM-DD
 		wg.Add(1)
-		sem <- struct{}{} // bloquear aqui se já tiver maxGoroutines em execução
+		sem <- struct{}{} // bloquear aqui se já tiver workers em execução
 		go func(index time.Time, dateStr string) {
 			defer wg.Done()
 			defer func() { <-sem }()
-			fear_api_alternative_me, err := getFearIndex(db, dateStr, "api.alternative.me")
-			if err != nil {
-				log.Printf("Fear index de alternative.me não encontrado para: %s", dateStr)
-				return
-			}
-
-			fear_coinmarketcap, err := getFearIndex(db, dateStr, "CoinMarketCap")
-			if err != nil {
-				log.Printf("Fear index de coinmarketcap não encontrado para: %s", dateStr)
-				return
-			}
-
-			if err := generateDatasetFile(index, cryptos, clearFiles, fear_api_alternative_me, fear_coinmarketcap); err != nil {
+			if err := generateDatasetFile(index, cryptos, config, featureSpecs, fear, perCoin, readBuffer); err != nil {
 				return
 			}
+			generatedMu.Lock()
+			generated[dateStr] = true
+			generatedMu.Unlock()
 		}(i, yearStr+"-"+monthStr+"-"+dayStr)
 	}
 	wg.Wait()
 
 	// Gera o arquivo final unificado entre a data inicial e a data final
-	isFullDatasetClear := false
-	isHeaderAdded := false
+	fullPath := finalDatasetPath("dataset_full", config.Format)
+	fullTempPath := fullPath + ".tmp"
+	var fullWriter datasetWriter
+	var fullManifest *datasetManifest
 	for i := initialDate; i.Before(time.Now().UTC()) && (i.Before(endDate) || i.Equal(endDate)); i = i.Add(24 * time.Hour) {
-		if !isFullDatasetClear {
-			if err := clearFinalDataset(); err != nil {
-				log.Printf("Erro ao limpar o arquivo de dataset dataset_full.csv: %v", err)
-				return
+		if !generated[i.Format("2006-01-02")] {
+			log.Printf("⏭️ %s não foi gerado e fica fora do dataset final", i.Format("2006-01-02"))
+			continue
+		}
+		if err := mergeDatasetFile(i, config, fullTempPath, &fullWriter, &fullManifest); err != nil {
+			log.Printf("Erro ao adicionar conteudo ao o arquivo de dataset %s: %v", fullPath, err)
+			if fullWriter != nil {
+				fullWriter.Close()
+				os.Remove(fullTempPath)
 			}
-			isFullDatasetClear = true
+			return
 		}
+	}
+	if fullWriter == nil {
+		log.Printf("Nenhum dia gerado entre %s e %s", initialDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+		return
+	}
+	if err := fullWriter.Close(); err != nil {
+		log.Printf("Erro ao finalizar o arquivo de dataset %s: %v", fullPath, err)
+		return
+	}
+	if err := os.Rename(fullTempPath, fullPath); err != nil {
+		log.Printf("Erro ao renomear o arquivo de dataset %s: %v", fullPath, err)
+		return
+	}
 
-		if err := mergeDatasetFile(i, &isHeaderAdded); err != nil {
-			log.Printf("Erro ao adicionar conteudo ao o arquivo de dataset dataset_full.csv: %v", err)
+	// Gera o dataset com os alvos percentuais usado pelos modelos. Vem antes dos
+	// rótulos, que descartam as últimas LabelHorizon linhas do dataset_full.
+	percentPath := finalDatasetPath("dataset_percent", config.Format)
+	header, rows, err := generatePercentDataset(config)
+	if err != nil {
+		log.Printf("Erro ao gerar o arquivo de dataset %s: %v", percentPath, err)
+		return
+	}
+	percentManifest := newDatasetManifest("dataset_percent", config, nil, fullManifest.Coins, header)
+	percentManifest.Percent = &manifestPercent{Reference: config.PercentReference, Horizon: config.PercentHorizon}
+	percentManifest.Rows = rows
+	if err := percentManifest.addSource(os.Getenv("DATASET_DIR"), fullPath, nil); err != nil {
+		log.Printf("Erro ao gerar manifesto de %s: %v", percentPath, err)
+		return
+	}
+	if err := percentManifest.write(percentPath); err != nil {
+		log.Printf("Erro ao salvar manifesto de %s: %v", percentPath, err)
+		return
+	}
+
+	// Acrescenta os rótulos ao dataset_full
+	if config.LabelHorizon > 0 {
+		header, rows, err := addLabels(config)
+		if err != nil {
+			log.Printf("Erro ao gerar os rótulos do dataset_full: %v", err)
 			return
 		}
+		fullManifest.Columns = manifestColumns(header)
+		fullManifest.Rows = rows
+		fullManifest.Labels = &manifestLabels{Horizon: config.LabelHorizon, Upper: config.LabelUpper, Lower: config.LabelLower}
+	}
+	if err := fullManifest.write(fullPath); err != nil {
+		log.Printf("Erro ao salvar manifesto de %s: %v", fullPath, err)
+		return
+	}
+
+	// Partes de uma execução anterior (outra divisão, outro formato ou sem
+	// divisão) não podem ficar ao lado do dataset novo
+	if err := os.RemoveAll(filepath.Join(os.Getenv("DATASET_DIR"), "splits")); err != nil {
+		log.Printf("Erro ao remover as divisões anteriores: %v", err)
+		return
+	}
+
+	// Divide os dois datasets com as mesmas fronteiras
+	if split != nil {
+		if err := writeSplits(split, config, fullManifest, percentManifest); err != nil {
+			log.Printf("Erro ao dividir os datasets: %v", err)
+			return
+		}
+	}
+}
+
+// writeSplits grava em DATASET_DIR/splits as partes do dataset_full e do dataset_percent
+func writeSplits(split *splitSpec, config Config, fullManifest, percentManifest *datasetManifest) error {
+	openTimes, err := readOpenTimes(finalDatasetPath("dataset_full", config.Format), config.Format)
+	if err != nil {
+		return err
+	}
+	segments, err := splitSegments(split, openTimes, config)
+	if err != nil {
+		return err
 	}
+	if err := exportSplits("dataset_full", config, segments, fullManifest); err != nil {
+		return err
+	}
+	return exportSplits("dataset_percent", config, segments, percentManifest)
 }
 
-func mergeDatasetFile(currentTime time.Time, isHeaderAdded *bool) error {
+// mergeDatasetFile copia as linhas do dataset do dia para o dataset final. O
+// writer do dataset final é criado no primeiro dia, com o cabeçalho dele.
+func mergeDatasetFile(currentTime time.Time, config Config, finalTempPath string, finalWriter *datasetWriter, finalManifest **datasetManifest) error {
 	yearStr := fixedCases(currentTime.Year())
 	monthStr := fixedCases(int(currentTime.Month()))
 	dayStr := fixedCases(currentTime.Day())
@@ -90,57 +331,71 @@ func mergeDatasetFile(currentTime time.Time, isHeaderAdded *bool) error {
 	// Gera a data no formato YYYY-MM-DD
 	dateStr := yearStr + "-" + monthStr + "-" + dayStr
 
-	currentDatasetDir := filepath.Join(os.Getenv("DATASET_DIR"), "cache", dateStr)
-	currentDatasetFilePath := filepath.Join(currentDatasetDir, "dataset-"+dateStr+".csv")
+	currentDatasetFilePath := cacheDatasetPath(dateStr, config)
 
-	finalDatasetDir := filepath.Join(os.Getenv("DATASET_DIR"))
-	finalDatasetFilePath := filepath.Join(finalDatasetDir, "dataset_full.csv")
+	// Dias com colunas diferentes não podem ser unidos no mesmo arquivo
+	dayManifest, err := readDatasetManifest(currentDatasetFilePath)
+	if err != nil {
+		return fmt.Errorf("manifesto de %s não encontrado, o dia não foi gerado com a configuração atual: %w", dateStr, err)
+	}
+	if *finalManifest != nil && !sameSchema(*finalManifest, dayManifest) {
+		return fmt.Errorf("colunas de %s diferem dos dias anteriores (moedas %v, esperado %v)", dateStr, dayManifest.Coins, (*finalManifest).Coins)
+	}
 
 	// Abre o arquivo de origem
-	sourceFile, err := os.Open(currentDatasetFilePath)
+	reader, err := openDatasetReader(currentDatasetFilePath, config.Format)
 	if err != nil {
 		return err
 	}
-	defer sourceFile.Close()
+	defer reader.Close()
+
+	if !reflect.DeepEqual(reader.Header(), columnNames(dayManifest.Columns)) {
+		return fmt.Errorf("cabeçalho de %s não confere com o manifesto", currentDatasetFilePath)
+	}
 
-	// Abre (ou cria) o arquivo final em modo append
-	destFile, err := os.OpenFile(finalDatasetFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if *finalWriter == nil {
+		writer, err := newDatasetWriter(finalTempPath, reader.Header(), config)
+		if err != nil {
+			return err
+		}
+		*finalWriter = writer
+		*finalManifest = newDatasetManifest("dataset_full", config, nil, dayManifest.Coins, reader.Header())
+		(*finalManifest).FeatureSpec = dayManifest.FeatureSpec
+		(*finalManifest).FeatureSpecHash = dayManifest.FeatureSpecHash
+	}
+
+	stat, err := os.Stat(currentDatasetFilePath)
 	if err != nil {
 		return err
 	}
-	defer destFile.Close()
-
-	sourceFile.Seek(0, 0)
-	scanner := bufio.NewScanner(sourceFile)
-	writer := bufio.NewWriter(destFile)
+	(*finalManifest).Sources = append((*finalManifest).Sources, manifestSource{
+		Path:    relativePath(os.Getenv("DATASET_DIR"), currentDatasetFilePath),
+		Size:    stat.Size(),
+		ModTime: stat.ModTime().UTC().Format(time.RFC3339Nano),
+		SHA256:  dayManifest.SHA256,
+	})
 
 	linesCount := 0
-	isHeaderLine := true
-
-	// Move scanner para inicio
-	for scanner.Scan() {
-		if isHeaderLine && *isHeaderAdded {
-			isHeaderLine = false
-			continue // ignora o cabeçalho
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
 		}
-		_, err := writer.WriteString(scanner.Text() + "\n")
 		if err != nil {
 			return err
 		}
-		*isHeaderAdded = true
+		if err := (*finalWriter).Write(fields); err != nil {
+			return err
+		}
 		linesCount++
 	}
-
-	if err := scanner.Err(); err != nil {
-		return err
-	}
+	(*finalManifest).Rows += linesCount
 
 	log.Printf("%d Linhas de %s", linesCount, currentDatasetFilePath)
-
-	return writer.Flush()
+	return nil
 }
 
-func generateDatasetFile(currentTime time.Time, cryptos []string, clearFiles bool, fear_api_alternative_me string, fear_coinmarketcap string) error {
+func generateDatasetFile(currentTime time.Time, cryptos []string, config Config, featureSpecs []features.Spec, fear []*fearSeries, perCoin *coinSentiment, readBuffer int) error {
 	yearStr := fixedCases(currentTime.Year())
 	monthStr := fixedCases(int(currentTime.Month()))
 	dayStr := fixedCases(currentTime.Day())
@@ -148,40 +403,84 @@ func generateDatasetFile(currentTime time.Time, cryptos []string, clearFiles boo
 	// Gera a data no formato YYYY-MM-DD
 	dateStr := yearStr + "-" + monthStr + "-" + dayStr
 
-	datasetDir := filepath.Join(os.Getenv("DATASET_DIR"), "cache", dateStr)
+	datasetFilePath := cacheDatasetPath(dateStr, config)
+	datasetDir := filepath.Dir(datasetFilePath)
 	datasetTempFilePath := filepath.Join(datasetDir, "dataset-"+dateStr+".tmp")
-	datasetFilePath := filepath.Join(datasetDir, "dataset-"+dateStr+".csv")
-
-	// Verifica se o arquivo de dataset já existe
-	if !clearFiles {
-		if _, err := os.Stat(datasetFilePath); err == nil {
-			log.Printf("✅ Arquivo de dataset já existe: %s", datasetFilePath)
-			return nil
-		}
-	}
 
 	klineBasePath := filepath.Join(os.Getenv("DATA_DIR"), "data.binance.vision/data/spot/daily/klines")
 
-	// Pre-carrega todos os klines para a memória (aprox 1440 por crypto)
-	// Mapa: crypto -> []models.BinanceKline
-	allKlines := make(map[string][]*models.BinanceKline)
+	// Quantidade de candles de um dia completo (1440 em 1m)
+	rowsPerDay := utils.RowsPerDay(config.Interval)
 
-	for _, crypto := range cryptos {
-		cryptoPair := crypto + "USDT"
-		filePath := filepath.Join(klineBasePath, cryptoPair, "1m", "csv", cryptoPair+"-1m-"+dateStr+".csv")
+	// Manifesto esperado para o dia: se o do cache for igual o arquivo é reaproveitado
+	template, err := features.NewSet(featureSpecs)
+	if err != nil {
+		return err
+	}
+	datasetHeader := buildDatasetHeader(cryptos, config, template.Columns(), perCoin != nil)
+	cached, _ := readDatasetManifest(datasetFilePath)
+	manifest, err := buildDayManifest(currentTime, cryptos, config, featureSpecs, datasetHeader, template.Warmup(), cached)
+	if err != nil {
+		log.Printf("Erro ao gerar manifesto de %s: %v", dateStr, err)
+		return err
+	}
 
-		klines, err := readAllKlines(filePath)
-		if err != nil {
-			log.Printf("Arquivo não encontrado ou erro ao ler: %s", filePath)
-			return err
+	// Cada linha do dataset corresponde a um OpenTime do dia
+	intervalDuration, _ := utils.IntervalDuration(config.Interval)
+	step := intervalDuration.Milliseconds()
+	dayStart := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), 0, 0, 0, 0, time.UTC).UnixMilli()
+	dayEnd := dayStart + int64(rowsPerDay)*step
+
+	// Pontos dos índices de medo que influenciam o dia
+	manifest.Fear = make(map[string]string)
+	for _, series := range fear {
+		if !series.covers(dayStart, dayEnd, config.FearInterpolate, config.FearMaxStaleness) && config.FearMissing == FearMissingSkip {
+			log.Printf("Fear index de %s não encontrado para: %s", series.Source, dateStr)
+			return fmt.Errorf("índice de medo %s ausente em %s", series.Source, dateStr)
 		}
-		if len(klines) < 1440 {
-			log.Printf("Aviso: Arquivo %s tem apenas %d linhas (esperado 1440)", filePath, len(klines))
+		manifest.Fear[series.Source] = series.describe(dayStart, dayEnd)
+	}
+	if perCoin != nil {
+		manifest.Sentiment = perCoin.manifest(dayStart, dayEnd)
+	}
+
+	// Verifica se o arquivo de dataset já existe
+	if !config.ClearFiles {
+		if _, err := os.Stat(datasetFilePath); err == nil {
+			if cached == nil {
+				log.Printf("♻️ Cache sem manifesto, gerando novamente: %s", datasetFilePath)
+			} else if reason := manifest.diff(cached); reason != "" {
+				log.Printf("♻️ Cache desatualizado (%s mudou), gerando novamente: %s", reason, datasetFilePath)
+			} else {
+				log.Printf("✅ Arquivo de dataset já existe: %s", datasetFilePath)
+				return nil
+			}
 		}
-		allKlines[crypto] = klines
 	}
 
-	log.Printf("Todos os arquivos carregados para a data: %s", dateStr)
+	// Um arquivo por crypto, lidos em conjunto em ordem de OpenTime
+	paths := make([]string, len(cryptos))
+	for c, crypto := range cryptos {
+		paths[c] = klineFilePath(klineBasePath, crypto+"USDT", config.Interval, currentTime)
+	}
+	merger, err := newKlineMerger(paths, readBuffer)
+	if err != nil {
+		log.Printf("Arquivo não encontrado ou erro ao ler: %v", err)
+		return err
+	}
+	defer merger.Close()
+
+	gapList := make([]*gapStats, len(cryptos))
+	for c, crypto := range cryptos {
+		gapList[c] = &gapStats{Symbol: crypto, Expected: rowsPerDay}
+	}
+
+	// Indicadores técnicos e último kline de cada crypto, aquecidos com os dias anteriores
+	states, err := newCoinStates(featureSpecs, cryptos, klineBasePath, config, currentTime, readBuffer)
+	if err != nil {
+		log.Printf("Erro ao criar indicadores: %v", err)
+		return err
+	}
 
 	// Cria diretório se não existir
 	if err := os.MkdirAll(datasetDir, 0755); err != nil {
@@ -189,160 +488,234 @@ func generateDatasetFile(currentTime time.Time, cryptos []string, clearFiles boo
 		return err
 	}
 
-	// Cria ou abre o arquivo de dataset para escrita
-	datasetFile, err := os.Create(datasetTempFilePath)
+	// Cria o arquivo de dataset e grava o cabeçalho
+	datasetWriter, err := newDatasetWriter(datasetTempFilePath, datasetHeader, config)
 	if err != nil {
 		log.Printf("Erro ao criar o arquivo temporário %s: %v", datasetTempFilePath, err)
 		return err
 	}
-	defer func() {
-		datasetFile.Close()
-		// Renomeia o arquivo de .tmp para .csv após a escrita bem-sucedida
-		if err := os.Rename(datasetTempFilePath, datasetFilePath); err != nil && !os.IsNotExist(err) {
-			log.Printf("Erro ao renomear o arquivo de dataset: %v", err)
-		}
-	}()
-
-	// Cria um writer para o arquivo de dataset
-	datasetWriter := bufio.NewWriter(datasetFile)
-
-	// Cria o cabeçalho do dataset
-	datasetHeader := []string{"OpenTime", "fear_api_alternative_me", "fear_coinmarketcap"}
-	for _, crypto := range cryptos {
-		datasetHeader = append(datasetHeader,
-			crypto+"_Open",
-			crypto+"_High",
-			crypto+"_Low",
-			crypto+"_Close",
-			crypto+"_Volume",
-			crypto+"_QuoteAssetVolume",
-			crypto+"_NumberOfTrades",
-			crypto+"_TakerBuyBaseVolume",
-			crypto+"_TakerBuyQuoteVolume",
-		)
-	}
 
-	// Grava o cabeçalho
-	if _, err := datasetWriter.WriteString(strings.Join(datasetHeader, ",") + "\n"); err != nil {
+	next, err := merger.Next()
+	if err != nil {
+		datasetWriter.Close()
 		return err
 	}
 
-	// Processa cada minuto (1440 por dia)
-	for i := 0; i < 1440; i++ {
-		var openTime int64
-		datasetLine := []string{fear_api_alternative_me, fear_coinmarketcap}
+	// Processa cada candle do dia
+	datasetLine := make([]float64, 0, len(datasetHeader))
+	droppedRows := 0
+	fearDroppedRows := 0
+	writtenRows := 0
+	for i := 0; i < rowsPerDay; i++ {
+		openTime := dayStart + int64(i)*step
+
+		// Klines fora do dia ou desalinhados com o intervalo são ignorados
+		for next != nil && next.OpenTime < openTime {
+			if next, err = merger.Next(); err != nil {
+				datasetWriter.Close()
+				return err
+			}
+		}
+		var current *mergedKlines
+		if next != nil && next.OpenTime == openTime {
+			current = next
+		}
+
+		datasetLine = append(datasetLine[:0], float64(openTime))
+		dropRow := false
+		for _, series := range fear {
+			value := series.at(openTime, config.FearInterpolate, config.FearMaxStaleness)
+			if math.IsNaN(value) && config.FearMissing == FearMissingSkip {
+				dropRow = true
+			}
+			datasetLine = append(datasetLine, value)
+		}
+		if dropRow {
+			fearDroppedRows++
+		}
+
+		for c, crypto := range cryptos {
+			stats := gapList[c]
+			state := states[c]
 
-		for _, crypto := range cryptos {
-			klines := allKlines[crypto]
-			var k *models.BinanceKline
-			if i < len(klines) {
-				k = klines[i]
+			var k *klineRow
+			if current != nil && current.Present[c] {
+				k = &current.Klines[c]
+				stats.Present++
+				state.last = *k
+				state.hasLast = true
 			} else {
-				// Fallback para kline vazio se faltar dados
-				k = &models.BinanceKline{}
+				if stats.Missing == 0 {
+					stats.FirstMissing = openTime
+				}
+				stats.Missing++
+
+				switch config.GapPolicy {
+				case GapForwardFill:
+					if state.hasLast {
+						filled := forwardFill(&state.last, openTime, step)
+						k = &filled
+						stats.Filled++
+					} else {
+						// Sem candle anterior não há o que repetir
+						dropRow = true
+					}
+				case GapDrop:
+					dropRow = true
+				}
 			}
 
-			if openTime == 0 && k.OpenTime != 0 {
-				openTime = k.OpenTime
+			if k == nil {
+				nan := math.NaN()
+				datasetLine = append(datasetLine, nan, nan, nan, nan, nan, nan, nan, nan, nan)
+			} else {
+				datasetLine = append(datasetLine,
+					k.Open,
+					k.High,
+					k.Low,
+					k.Close,
+					k.Volume,
+					k.QuoteAssetVolume,
+					float64(k.NumberOfTrades),
+					k.TakerBuyBaseVolume,
+					k.TakerBuyQuoteVolume,
+				)
+			}
+			if config.GapPolicy == GapNaN {
+				if k == nil {
+					datasetLine = append(datasetLine, 1)
+				} else {
+					datasetLine = append(datasetLine, 0)
+				}
+			}
+			if perCoin != nil {
+				datasetLine = append(datasetLine, perCoin.at(c, openTime, config.FearInterpolate, config.FearMaxStaleness))
 			}
+			if state.features != nil {
+				values := state.features.Empty()
+				if k != nil {
+					values = state.features.Update(k.candle(crypto))
+				}
+				datasetLine = append(datasetLine, values...)
+			}
+		}
 
-			datasetLine = append(datasetLine,
-				k.Open,
-				k.High,
-				k.Low,
-				k.Close,
-				k.Volume,
-				k.QuoteAssetVolume,
-				strconv.Itoa(k.NumberOfTrades),
-				k.TakerBuyBaseVolume,
-				k.TakerBuyQuoteVolume,
-			)
+		if dropRow {
+			droppedRows++
+			continue
 		}
 
-		// Prepend OpenTime
-		finalLine := append([]string{strconv.FormatInt(openTime, 10)}, datasetLine...)
-		if _, err := datasetWriter.WriteString(strings.Join(finalLine, ",") + "\n"); err != nil {
+		if err := datasetWriter.Write(datasetLine); err != nil {
+			datasetWriter.Close()
 			return err
 		}
+		writtenRows++
+	}
+
+	if err := datasetWriter.Close(); err != nil {
+		return err
 	}
 
-	if err := datasetWriter.Flush(); err != nil {
+	// Renomeia o arquivo .tmp após a escrita bem-sucedida
+	if err := os.Rename(datasetTempFilePath, datasetFilePath); err != nil {
+		log.Printf("Erro ao renomear o arquivo de dataset: %v", err)
 		return err
 	}
 
+	manifest.Rows = writtenRows
+	if err := manifest.write(datasetFilePath); err != nil {
+		log.Printf("Erro ao salvar manifesto do dataset: %v", err)
+		return err
+	}
+
+	// Relatório de gaps por crypto
+	for _, stats := range gapList {
+		if stats.Missing > 0 {
+			log.Printf("🕳️ %s %s: %d de %d candles ausentes (%d preenchidos)", dateStr, stats.Symbol, stats.Missing, stats.Expected, stats.Filled)
+		}
+	}
+	if fearDroppedRows > 0 {
+		log.Printf("🕳️ %s: %d linhas sem índice de medo descartadas", dateStr, fearDroppedRows)
+	}
+	if droppedRows > fearDroppedRows {
+		log.Printf("🕳️ %s: %d linhas descartadas pela política de gaps %q", dateStr, droppedRows-fearDroppedRows, config.GapPolicy)
+	}
+	gapReportPath := filepath.Join(datasetDir, "gaps-"+dateStr+".csv")
+	if err := writeGapReport(gapReportPath, gapList, droppedRows); err != nil {
+		log.Printf("Erro ao salvar relatório de gaps: %v", err)
+	}
+
 	log.Printf("Dataset gerado com sucesso em: %s", datasetFilePath)
 	return nil
 }
 
-func readAllKlines(filePath string) ([]*models.BinanceKline, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, err
+// buildDatasetHeader monta o cabeçalho do dataset do dia
+func buildDatasetHeader(cryptos []string, config Config, featureColumns []string, withSentiment bool) []string {
+	datasetHeader := []string{"OpenTime"}
+	for _, source := range sentiment.Sources() {
+		datasetHeader = append(datasetHeader, source.Column())
 	}
-	defer file.Close()
-
-	var klines []*models.BinanceKline
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		fields := strings.Split(line, ",")
-		if len(fields) >= 12 {
-			klines = append(klines, &models.BinanceKline{
-				OpenTime:            toInt64(fields[0]),
-				Open:                fields[1],
-				High:                fields[2],
-				Low:                 fields[3],
-				Close:               fields[4],
-				Volume:              fields[5],
-				CloseTime:           toInt64(fields[6]),
-				QuoteAssetVolume:    fields[7],
-				NumberOfTrades:      toInt(fields[8]),
-				TakerBuyBaseVolume:  fields[9],
-				TakerBuyQuoteVolume: fields[10],
-				Ignore:              fields[11],
-			})
+	for _, crypto := range cryptos {
+		datasetHeader = append(datasetHeader,
+			crypto+"_Open",
+			crypto+"_High",
+			crypto+"_Low",
+			crypto+"_Close",
+			crypto+"_Volume",
+			crypto+"_QuoteAssetVolume",
+			crypto+"_NumberOfTrades",
+			crypto+"_TakerBuyBaseVolume",
+			crypto+"_TakerBuyQuoteVolume",
+		)
+		if config.GapPolicy == GapNaN {
+			datasetHeader = append(datasetHeader, crypto+"_missing")
+		}
+		if withSentiment {
+			datasetHeader = append(datasetHeader, crypto+"_sentiment")
+		}
+		for _, column := range featureColumns {
+			datasetHeader = append(datasetHeader, crypto+"_"+column)
 		}
 	}
-	return klines, scanner.Err()
+	return datasetHeader
 }
 
-func getFearIndex(db *sql.DB, dateStr string, sourceStr string) (string, error) {
-	query := `
-        SELECT value
-        FROM fear_index
-        WHERE date LIKE ? AND source = ?;
-    `
-	var fearIndex float64
-	err := db.QueryRow(query, "%"+dateStr+"%", sourceStr).Scan(&fearIndex)
-	if err != nil {
-		return "0", err
+// buildDayManifest descreve o dataset do dia, incluindo os klines do dia e dos
+// dias anteriores usados no forward-fill e no aquecimento dos indicadores.
+func buildDayManifest(currentTime time.Time, cryptos []string, config Config, featureSpecs []features.Spec, header []string, warmup int, cached *datasetManifest) (*datasetManifest, error) {
+	manifest := newDatasetManifest("cache", config, featureSpecs, cryptos, header)
+	manifest.Start = currentTime.Format("2006-01-02")
+	manifest.End = manifest.Start
+
+	rowsPerDay := utils.RowsPerDay(config.Interval)
+	previousDays := (warmup + rowsPerDay - 1) / rowsPerDay
+	if config.GapPolicy == GapForwardFill && previousDays == 0 {
+		previousDays = 1
 	}
-	return strconv.FormatFloat(fearIndex, 'f', -1, 64), nil
-}
-
-func clearFinalDataset() error {
-	finalDatasetDir := filepath.Join(os.Getenv("DATASET_DIR"))
-	finalDatasetFilePath := filepath.Join(finalDatasetDir, "dataset_full.csv")
 
-	// Cria ou abre o arquivo de dataset para escrita (append ou novo)
-	datasetFile, err := os.OpenFile(finalDatasetFilePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-	if err != nil {
-		log.Printf("Erro ao abrir ou criar o arquivo de dataset %s: %v", finalDatasetFilePath, err)
-		return err
+	dataDir := os.Getenv("DATA_DIR")
+	klineBasePath := filepath.Join(dataDir, "data.binance.vision/data/spot/daily/klines")
+	for _, crypto := range cryptos {
+		cryptoPair := crypto + "USDT"
+		for d := previousDays; d >= 0; d-- {
+			dateStr := currentTime.AddDate(0, 0, -d).Format("2006-01-02")
+			filePath := filepath.Join(klineBasePath, cryptoPair, config.Interval, "csv", cryptoPair+"-"+config.Interval+"-"+dateStr+".csv")
+			if err := manifest.addSource(dataDir, filePath, cached); err != nil {
+				return nil, err
+			}
+		}
 	}
-	defer datasetFile.Close()
-
-	// Cria um writer para o arquivo de dataset
-	datasetWriter := bufio.NewWriter(datasetFile)
-	defer datasetWriter.Flush()
+	return manifest, nil
+}
 
-	// Limpa o arquivo de dataset se já existir
-	if err := datasetFile.Truncate(0); err != nil {
-		log.Printf("Erro ao limpar o arquivo de dataset %s: %v", finalDatasetFilePath, err)
-		return err
-	}
+// Caminho do dataset do dia no cache, separado por intervalo
+func cacheDatasetPath(dateStr string, config Config) string {
+	return filepath.Join(os.Getenv("DATASET_DIR"), "cache", config.Interval, dateStr, "dataset-"+dateStr+"."+config.Format)
+}
 
-	return nil
+// Caminho de um dataset final (dataset_full, dataset_percent) no formato escolhido
+func finalDatasetPath(name, format string) string {
+	return filepath.Join(os.Getenv("DATASET_DIR"), name+"."+format)
 }
 
 // busca criptomoedas da binance habilitadas
@@ -353,7 +726,8 @@ func fetchEnabledCryptos(db *sql.DB) ([]string, error) {
         JOIN exchanges_cryptos ec ON c.id = ec.crypto_id
         JOIN exchanges e ON ec.exchange_id = e.id
         WHERE LOWER(e.name) LIKE '%binance%'
-        AND c.is_enabled = 1;
+        AND c.is_enabled = 1
+        ORDER BY c.symbol;
     `
 	rows, err := db.Query(query)
 	if err != nil {
@@ -378,21 +752,3 @@ func fixedCases(value int) string {
 	}
 	return strconv.Itoa(value)
 }
-
-func toInt(value string) int {
-	parsedValue, err := strconv.Atoi(value)
-	if err != nil {
-		log.Printf("Erro ao converter %s para int: %v", value, err)
-		return 0
-	}
-	return parsedValue
-}
-
-func toInt64(value string) int64 {
-	parsedValue, err := strconv.ParseInt(value, 10, 64)
-	if err != nil {
-		log.Printf("Erro ao converter %s para int64: %v", value, err)
-		return 0
-	}
-	return parsedValue
-}
@@ -0,0 +1,269 @@
+package generateDataset
+
+import (
+	"app/src/features"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Coluna do dataset com o tipo gravado no parquet (e esperado ao ler o CSV)
+type manifestColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Arquivo usado para gerar o dataset. Tamanho e data de modificação permitem
+// reaproveitar o SHA-256 sem reler arquivos que não mudaram.
+type manifestSource struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	ModTime string `json:"mod_time"`
+	SHA256  string `json:"sha256"`
+}
+
+type manifestPercent struct {
+	Reference string `json:"reference"`
+	Horizon   int    `json:"horizon"`
+}
+
+// Parte de uma divisão cronológica, com o intervalo [from, to) de OpenTime
+type manifestSplit struct {
+	Name    string `json:"name"`
+	Fold    int    `json:"fold,omitempty"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Embargo int    `json:"embargo"`
+}
+
+// Como os índices de medo foram alinhados às linhas
+type manifestFearPolicy struct {
+	Interpolate  bool   `json:"interpolate"`
+	MaxStaleness string `json:"max_staleness"`
+	Missing      string `json:"missing"`
+}
+
+// Sentimento por crypto: fonte, índice de mercado de fallback e os pontos de
+// cada um que influenciam o arquivo
+type manifestSentiment struct {
+	Source         string            `json:"source"`
+	Points         map[string]string `json:"points"`
+	Fallback       string            `json:"fallback"`
+	FallbackPoints string            `json:"fallback_points"`
+}
+
+type manifestLabels struct {
+	Horizon int     `json:"horizon"`
+	Upper   float64 `json:"upper"`
+	Lower   float64 `json:"lower"`
+}
+
+// datasetManifest descreve um dataset gerado e é gravado ao lado dele em
+// <arquivo>.manifest.json. No cache diário também serve para invalidar o
+// arquivo quando moedas, colunas, configuração ou fontes mudam.
+type datasetManifest struct {
+	Dataset         string              `json:"dataset"`
+	Format          string              `json:"format"`
+	Interval        string              `json:"interval"`
+	GapPolicy       string              `json:"gap_policy"`
+	Start           string              `json:"start"`
+	End             string              `json:"end"`
+	Coins           []string            `json:"coins"`
+	Columns         []manifestColumn    `json:"columns"`
+	FeatureSpec     string              `json:"feature_spec"`
+	FeatureSpecHash string              `json:"feature_spec_hash"`
+	Fear            map[string]string   `json:"fear,omitempty"`
+	FearPolicy      *manifestFearPolicy `json:"fear_policy,omitempty"`
+	Sentiment       *manifestSentiment  `json:"sentiment,omitempty"`
+	Percent         *manifestPercent    `json:"percent,omitempty"`
+	Labels          *manifestLabels     `json:"labels,omitempty"`
+	Split           *manifestSplit      `json:"split,omitempty"`
+	Sources         []manifestSource    `json:"sources"`
+	Rows            int                 `json:"rows"`
+	SHA256          string              `json:"sha256,omitempty"`
+	GeneratedAt     string              `json:"generated_at,omitempty"`
+}
+
+func newDatasetManifest(dataset string, config Config, featureSpecs []features.Spec, coins, header []string) *datasetManifest {
+	spec := features.FormatSpec(featureSpecs)
+	specHash := sha256.Sum256([]byte(spec))
+
+	return &datasetManifest{
+		Dataset:         dataset,
+		Format:          config.Format,
+		Interval:        config.Interval,
+		GapPolicy:       config.GapPolicy,
+		Start:           config.Start.Format("2006-01-02"),
+		End:             config.End.Format("2006-01-02"),
+		Coins:           coins,
+		Columns:         manifestColumns(header),
+		FeatureSpec:     spec,
+		FeatureSpecHash: hex.EncodeToString(specHash[:]),
+		FearPolicy: &manifestFearPolicy{
+			Interpolate:  config.FearInterpolate,
+			MaxStaleness: config.FearMaxStaleness.String(),
+			Missing:      config.FearMissing,
+		},
+	}
+}
+
+// manifestColumns associa cada coluna do cabeçalho ao seu tipo
+func manifestColumns(header []string) []manifestColumn {
+	columns := make([]manifestColumn, len(header))
+	for i, name := range header {
+		columns[i] = manifestColumn{Name: name, Type: "float64"}
+		if isIntColumn(name) {
+			columns[i].Type = "int64"
+		}
+	}
+	return columns
+}
+
+// Caminho do manifesto de um arquivo de dataset
+func manifestPath(datasetPath string) string {
+	return datasetPath + ".manifest.json"
+}
+
+func readDatasetManifest(datasetPath string) (*datasetManifest, error) {
+	data, err := os.ReadFile(manifestPath(datasetPath))
+	if err != nil {
+		return nil, err
+	}
+	var manifest datasetManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("manifesto inválido %s: %w", manifestPath(datasetPath), err)
+	}
+	return &manifest, nil
+}
+
+// write grava o manifesto junto do dataset, com o hash do próprio arquivo
+func (m *datasetManifest) write(datasetPath string) error {
+	hash, err := fileSHA256(datasetPath)
+	if err != nil {
+		return err
+	}
+	m.SHA256 = hash
+	m.GeneratedAt = time.Now().UTC().Format(time.RFC3339)
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tempPath := manifestPath(datasetPath) + ".tmp"
+	if err := os.WriteFile(tempPath, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, manifestPath(datasetPath))
+}
+
+// addSource registra um arquivo de origem, reaproveitando o hash de previous
+// quando tamanho e data de modificação não mudaram. Arquivos inexistentes são ignorados.
+func (m *datasetManifest) addSource(baseDir, path string, previous *datasetManifest) error {
+	stat, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	source := manifestSource{
+		Path:    relativePath(baseDir, path),
+		Size:    stat.Size(),
+		ModTime: stat.ModTime().UTC().Format(time.RFC3339Nano),
+	}
+
+	if previous != nil {
+		for _, p := range previous.Sources {
+			if p.Path == source.Path && p.Size == source.Size && p.ModTime == source.ModTime {
+				source.SHA256 = p.SHA256
+				break
+			}
+		}
+	}
+	if source.SHA256 == "" {
+		if source.SHA256, err = fileSHA256(path); err != nil {
+			return err
+		}
+	}
+
+	m.Sources = append(m.Sources, source)
+	return nil
+}
+
+// diff compara o manifesto esperado com o do cache e descreve a primeira
+// diferença encontrada. Retorna "" quando o cache pode ser reaproveitado.
+func (m *datasetManifest) diff(cached *datasetManifest) string {
+	switch {
+	case cached.Format != m.Format:
+		return "formato"
+	case cached.Interval != m.Interval:
+		return "intervalo"
+	case cached.GapPolicy != m.GapPolicy:
+		return "política de gaps"
+	case !reflect.DeepEqual(cached.Coins, m.Coins):
+		return "lista de moedas"
+	case !reflect.DeepEqual(cached.Columns, m.Columns):
+		return "colunas"
+	case cached.FeatureSpecHash != m.FeatureSpecHash:
+		return "spec de features"
+	case !reflect.DeepEqual(cached.FearPolicy, m.FearPolicy):
+		return "política dos índices de medo"
+	case !reflect.DeepEqual(cached.Fear, m.Fear):
+		return "índices de medo"
+	case !reflect.DeepEqual(cached.Sentiment, m.Sentiment):
+		return "sentimento por crypto"
+	}
+
+	if len(cached.Sources) != len(m.Sources) {
+		return "arquivos de origem"
+	}
+	for i := range m.Sources {
+		if cached.Sources[i].Path != m.Sources[i].Path || cached.Sources[i].SHA256 != m.Sources[i].SHA256 {
+			return "arquivo de origem " + m.Sources[i].Path
+		}
+	}
+	return ""
+}
+
+// sameSchema indica se dois datasets têm exatamente as mesmas colunas
+func sameSchema(a, b *datasetManifest) bool {
+	return reflect.DeepEqual(a.Columns, b.Columns)
+}
+
+func columnNames(columns []manifestColumn) []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+func relativePath(baseDir, path string) string {
+	rel, err := filepath.Rel(baseDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
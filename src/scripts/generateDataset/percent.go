@@ -0,0 +1,111 @@
+package generateDataset
+
+import (
+	"app/src/utils"
+	"log"
+	"math"
+	"os"
+)
+
+// Preço de referência dos alvos percentuais
+const (
+	// Fechamento do candle da linha
+	PercentReferenceClose = "close"
+	// Abertura do candle da linha
+	PercentReferenceOpen = "open"
+)
+
+var PercentReferences = []string{PercentReferenceClose, PercentReferenceOpen}
+
+func isValidPercentReference(reference string) bool {
+	for _, r := range PercentReferences {
+		if r == reference {
+			return true
+		}
+	}
+	return false
+}
+
+// generatePercentDataset lê o dataset_full e grava o dataset_percent (no
+// formato do config) lido pelos modelos do model-generator. Para cada crypto:
+//
+//	<COIN>_PercentHigh = (maior High dos próximos horizon candles - referência) / referência * 100
+//	<COIN>_PercentLow  = (menor Low dos próximos horizon candles - referência) / referência * 100
+//
+// Com horizon 0 são usados o High e o Low do próprio candle. Linhas sem o
+// horizonte completo (fim do período ou gap no meio) não são gravadas.
+func generatePercentDataset(config Config) ([]string, int, error) {
+	fullPath := finalDatasetPath("dataset_full", config.Format)
+	percentPath := finalDatasetPath("dataset_percent", config.Format)
+	tempPath := percentPath + ".tmp"
+
+	reader, err := openDatasetReader(fullPath, config.Format)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer reader.Close()
+	header := reader.Header()
+
+	coins, fearColumns, err := datasetColumns(header)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	percentHeader := append([]string{"OpenTime"}, headerNames(header, fearColumns)...)
+	for _, coin := range coins {
+		percentHeader = append(percentHeader,
+			coin.Symbol+"_Close",
+			coin.Symbol+"_PercentHigh",
+			coin.Symbol+"_PercentLow",
+		)
+	}
+	writer, err := newDatasetWriter(tempPath, percentHeader, config)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	intervalDuration, _ := utils.IntervalDuration(config.Interval)
+	horizon := config.PercentHorizon
+
+	written, skipped, err := streamHorizon(reader, coins, horizon, intervalDuration.Milliseconds(), func(row *horizonRow, future []*horizonRow) error {
+		line := []float64{float64(row.OpenTime)}
+		for _, column := range fearColumns {
+			line = append(line, row.Fields[column])
+		}
+		for c, coin := range coins {
+			reference := row.Close[c]
+			if config.PercentReference == PercentReferenceOpen {
+				reference = row.Open[c]
+			}
+			high, low := row.High[c], row.Low[c]
+			if horizon > 0 {
+				high, low = math.Inf(-1), math.Inf(1)
+				for _, f := range future {
+					high = nanMax(high, f.High[c])
+					low = nanMin(low, f.Low[c])
+				}
+			}
+			line = append(line,
+				row.Fields[coin.Close],
+				percentOf(high, reference),
+				percentOf(low, reference),
+			)
+		}
+		return writer.Write(line)
+	})
+	if err != nil {
+		writer.Close()
+		return nil, 0, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := os.Rename(tempPath, percentPath); err != nil {
+		return nil, 0, err
+	}
+
+	log.Printf("📈 %s gerado com %d linhas (referência %s, horizonte %d, %d linhas sem horizonte completo)",
+		percentPath, written, config.PercentReference, horizon, skipped)
+	return percentHeader, written, nil
+}
@@ -0,0 +1,87 @@
+package generateDataset
+
+import (
+	"database/sql"
+	"log"
+	"math"
+	"time"
+)
+
+// coinSentiment são as séries de sentimento por crypto (target = símbolo no
+// fear_index) emitidas como <COIN>_sentiment
+type coinSentiment struct {
+	Source string
+	// Série de cada crypto, na ordem das cryptos do dataset (nil sem série própria)
+	series []*fearSeries
+	// Índice de mercado usado quando a crypto não tem valor (nil usa NaN)
+	fallback *fearSeries
+}
+
+// loadCoinSentiment carrega as séries por crypto de config.Sentiment. Retorna
+// nil quando o sentimento está desativado ou nenhuma crypto tem série, e o
+// dataset fica sem as colunas <COIN>_sentiment.
+func loadCoinSentiment(db *sql.DB, config Config, cryptos []string) (*coinSentiment, error) {
+	if config.Sentiment == "" {
+		return nil, nil
+	}
+
+	s := &coinSentiment{Source: config.Sentiment, series: make([]*fearSeries, len(cryptos))}
+	withSeries := 0
+	for c, crypto := range cryptos {
+		series, err := loadFearSeries(db, config.Sentiment, crypto)
+		if err != nil {
+			return nil, err
+		}
+		if len(series.points) > 0 {
+			s.series[c] = series
+			withSeries++
+		}
+	}
+	if withSeries == 0 {
+		return nil, nil
+	}
+
+	if config.SentimentFallback != "" {
+		fallback, err := loadFearSeries(db, config.SentimentFallback, "")
+		if err != nil {
+			return nil, err
+		}
+		s.fallback = fallback
+	}
+
+	fallback := "NaN"
+	if s.fallback != nil {
+		fallback = s.fallback.Source
+	}
+	log.Printf("🧠 Sentimento por crypto de %s: %d de %d cryptos com série própria (demais usam %s)", s.Source, withSeries, len(cryptos), fallback)
+	return s, nil
+}
+
+// at é o sentimento da crypto c no OpenTime t, com o mesmo alinhamento as-of
+// dos índices de medo. Sem valor próprio usa o índice de mercado.
+func (s *coinSentiment) at(c int, t int64, interpolate bool, maxStaleness time.Duration) float64 {
+	if series := s.series[c]; series != nil {
+		if value := series.at(t, interpolate, maxStaleness); !math.IsNaN(value) {
+			return value
+		}
+	}
+	if s.fallback != nil {
+		return s.fallback.at(t, interpolate, maxStaleness)
+	}
+	return math.NaN()
+}
+
+// manifest descreve os pontos que influenciam [from, to), usado no manifesto do dia
+func (s *coinSentiment) manifest(from, to int64) *manifestSentiment {
+	m := &manifestSentiment{Source: s.Source, Points: make(map[string]string)}
+	for _, series := range s.series {
+		if series != nil {
+			m.Points[series.Target] = series.describe(from, to)
+		}
+	}
+	if s.fallback != nil {
+		m.Fallback = s.fallback.Source
+		m.FallbackPoints = s.fallback.describe(from, to)
+	}
+	return m
+}
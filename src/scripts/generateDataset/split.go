@@ -0,0 +1,316 @@
+package generateDataset
+
+import (
+	"app/src/utils"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Partes da divisão cronológica
+const (
+	SplitTrain      = "train"
+	SplitValidation = "val"
+	SplitTest       = "test"
+)
+
+// splitSpec é a divisão configurada: proporções de linhas ou datas de início
+// da validação e do teste. Sem nenhuma das duas apenas os folds são gerados.
+type splitSpec struct {
+	ratios []float64
+	dates  []time.Time
+}
+
+// splitSegment é um arquivo gerado pela divisão: as linhas com OpenTime em [From, To)
+type splitSegment struct {
+	Name string
+	// Fold do walk-forward (0 na divisão treino/validação/teste)
+	Fold int
+	From int64
+	To   int64
+}
+
+// parseSplit valida a divisão do config. Retorna nil quando nenhuma divisão foi pedida.
+func parseSplit(config Config) (*splitSpec, error) {
+	if config.Embargo < 0 {
+		return nil, fmt.Errorf("embargo inválido: %d", config.Embargo)
+	}
+	if config.Folds < 0 {
+		return nil, fmt.Errorf("quantidade de folds inválida: %d", config.Folds)
+	}
+	if config.SplitRatios != "" && config.SplitDates != "" {
+		return nil, fmt.Errorf("use proporções ou datas na divisão, não ambas")
+	}
+
+	spec := &splitSpec{}
+	if config.SplitRatios != "" {
+		sum := 0.0
+		for _, raw := range strings.Split(config.SplitRatios, ",") {
+			ratio, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+			if err != nil || ratio < 0 {
+				return nil, fmt.Errorf("proporção inválida: %q", raw)
+			}
+			spec.ratios = append(spec.ratios, ratio)
+			sum += ratio
+		}
+		if len(spec.ratios) < 2 || len(spec.ratios) > 3 {
+			return nil, fmt.Errorf("informe 2 (treino,teste) ou 3 (treino,validação,teste) proporções: %s", config.SplitRatios)
+		}
+		if math.Abs(sum-1) > 1e-9 {
+			return nil, fmt.Errorf("as proporções devem somar 1: %s", config.SplitRatios)
+		}
+	}
+	if config.SplitDates != "" {
+		for _, raw := range strings.Split(config.SplitDates, ",") {
+			date, err := time.Parse("2006-01-02", strings.TrimSpace(raw))
+			if err != nil {
+				return nil, fmt.Errorf("data inválida na divisão: %q", raw)
+			}
+			if len(spec.dates) > 0 && !date.After(spec.dates[len(spec.dates)-1]) {
+				return nil, fmt.Errorf("as datas da divisão devem ser crescentes: %s", config.SplitDates)
+			}
+			spec.dates = append(spec.dates, date)
+		}
+		if len(spec.dates) > 2 {
+			return nil, fmt.Errorf("informe 1 (teste) ou 2 (validação,teste) datas: %s", config.SplitDates)
+		}
+	}
+
+	if spec.ratios == nil && spec.dates == nil && config.Folds == 0 {
+		return nil, nil
+	}
+	return spec, nil
+}
+
+// splitSegments calcula os intervalos de cada arquivo a partir dos OpenTime do
+// dataset_full, para que todos os datasets usem as mesmas fronteiras. Os
+// Embargo candles anteriores a cada fronteira ficam fora da parte anterior,
+// evitando que rótulos e alvos vejam dados da parte seguinte.
+func splitSegments(spec *splitSpec, openTimes []int64, config Config) ([]splitSegment, error) {
+	if len(openTimes) == 0 {
+		return nil, fmt.Errorf("dataset vazio")
+	}
+	intervalDuration, _ := utils.IntervalDuration(config.Interval)
+	embargo := int64(config.Embargo) * intervalDuration.Milliseconds()
+	end := openTimes[len(openTimes)-1] + 1
+
+	// Início de cada parte depois do treino
+	var boundaries []int64
+	switch {
+	case spec.ratios != nil:
+		cumulative := 0.0
+		for _, ratio := range spec.ratios[:len(spec.ratios)-1] {
+			cumulative += ratio
+			index := int(float64(len(openTimes)) * cumulative)
+			if index >= len(openTimes) {
+				return nil, fmt.Errorf("proporções deixam partes vazias com %d linhas", len(openTimes))
+			}
+			boundaries = append(boundaries, openTimes[index])
+		}
+	case spec.dates != nil:
+		for _, date := range spec.dates {
+			boundaries = append(boundaries, date.UnixMilli())
+		}
+	}
+
+	var segments []splitSegment
+	if len(boundaries) > 0 {
+		names := []string{SplitTrain, SplitTest}
+		if len(boundaries) == 2 {
+			names = []string{SplitTrain, SplitValidation, SplitTest}
+		}
+		from := openTimes[0]
+		for i, name := range names {
+			to := end
+			if i < len(boundaries) {
+				to = boundaries[i] - embargo
+			}
+			segments = append(segments, splitSegment{Name: name, From: from, To: to})
+			if i < len(boundaries) {
+				from = boundaries[i]
+			}
+		}
+	}
+
+	// Walk-forward: o período antes do teste (ou todo o dataset) é dividido em
+	// Folds+1 blocos com a mesma quantidade de linhas. O fold k treina no bloco
+	// k e testa no bloco k+1.
+	if config.Folds > 0 {
+		limit := end
+		if len(boundaries) > 0 {
+			limit = boundaries[len(boundaries)-1]
+		}
+		rows := 0
+		for rows < len(openTimes) && openTimes[rows] < limit {
+			rows++
+		}
+		blockSize := rows / (config.Folds + 1)
+		if blockSize == 0 {
+			return nil, fmt.Errorf("%d linhas não bastam para %d folds", rows, config.Folds)
+		}
+		blockStart := func(block int) int64 {
+			if block > config.Folds {
+				return limit
+			}
+			return openTimes[block*blockSize]
+		}
+		for k := 1; k <= config.Folds; k++ {
+			segments = append(segments,
+				splitSegment{Name: SplitTrain, Fold: k, From: blockStart(k - 1), To: blockStart(k) - embargo},
+				splitSegment{Name: SplitTest, Fold: k, From: blockStart(k), To: blockStart(k + 1)},
+			)
+		}
+	}
+
+	for _, segment := range segments {
+		if segment.To <= segment.From {
+			return nil, fmt.Errorf("parte %s vazia: fronteiras fora do dataset ou embargo de %d candles maior que a parte (dataset de %s a %s)",
+				segment.label(), config.Embargo,
+				time.UnixMilli(openTimes[0]).UTC().Format(time.RFC3339), time.UnixMilli(end).UTC().Format(time.RFC3339))
+		}
+	}
+	return segments, nil
+}
+
+func (s splitSegment) label() string {
+	if s.Fold > 0 {
+		return fmt.Sprintf("fold %d %s", s.Fold, s.Name)
+	}
+	return s.Name
+}
+
+// path é o arquivo da parte em DATASET_DIR/splits (ou splits/fold_<k>)
+func (s splitSegment) path(dataset, format string) string {
+	dir := filepath.Join(os.Getenv("DATASET_DIR"), "splits")
+	if s.Fold > 0 {
+		dir = filepath.Join(dir, fmt.Sprintf("fold_%d", s.Fold))
+	}
+	return filepath.Join(dir, dataset+"_"+s.Name+"."+format)
+}
+
+// readOpenTimes retorna a coluna OpenTime de todas as linhas de um dataset
+func readOpenTimes(path, format string) ([]int64, error) {
+	reader, err := openDatasetReader(path, format)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var openTimes []int64
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return openTimes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		openTimes = append(openTimes, int64(values[0]))
+	}
+}
+
+// exportSplits grava as partes de um dataset em uma única leitura, cada uma
+// com seu manifesto (derivado do manifesto do dataset de origem).
+func exportSplits(dataset string, config Config, segments []splitSegment, parent *datasetManifest) error {
+	sourcePath := finalDatasetPath(dataset, config.Format)
+	reader, err := openDatasetReader(sourcePath, config.Format)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// O dataset de origem já tem o hash no manifesto dele
+	stat, err := os.Stat(sourcePath)
+	if err != nil {
+		return err
+	}
+	source := manifestSource{
+		Path:    relativePath(os.Getenv("DATASET_DIR"), sourcePath),
+		Size:    stat.Size(),
+		ModTime: stat.ModTime().UTC().Format(time.RFC3339Nano),
+		SHA256:  parent.SHA256,
+	}
+
+	writers := make([]datasetWriter, len(segments))
+	rows := make([]int, len(segments))
+	closeAll := func() {
+		for i, writer := range writers {
+			if writer != nil {
+				writer.Close()
+				os.Remove(segments[i].path(dataset, config.Format) + ".tmp")
+			}
+		}
+	}
+	for i, segment := range segments {
+		path := segment.path(dataset, config.Format)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			closeAll()
+			return err
+		}
+		if writers[i], err = newDatasetWriter(path+".tmp", reader.Header(), config); err != nil {
+			closeAll()
+			return err
+		}
+	}
+
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			closeAll()
+			return err
+		}
+		openTime := int64(values[0])
+		for i, segment := range segments {
+			if openTime >= segment.From && openTime < segment.To {
+				if err := writers[i].Write(values); err != nil {
+					closeAll()
+					return err
+				}
+				rows[i]++
+			}
+		}
+	}
+
+	for i, segment := range segments {
+		path := segment.path(dataset, config.Format)
+		err := writers[i].Close()
+		writers[i] = nil
+		if err != nil {
+			closeAll()
+			return err
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			closeAll()
+			return err
+		}
+
+		manifest := *parent
+		manifest.Dataset = dataset + "_" + segment.Name
+		manifest.Start = time.UnixMilli(segment.From).UTC().Format("2006-01-02")
+		manifest.End = time.UnixMilli(segment.To - 1).UTC().Format("2006-01-02")
+		manifest.Split = &manifestSplit{
+			Name:    segment.Name,
+			Fold:    segment.Fold,
+			From:    time.UnixMilli(segment.From).UTC().Format(time.RFC3339),
+			To:      time.UnixMilli(segment.To).UTC().Format(time.RFC3339),
+			Embargo: config.Embargo,
+		}
+		manifest.Rows = rows[i]
+		manifest.Sources = []manifestSource{source}
+		if err := manifest.write(path); err != nil {
+			return err
+		}
+
+		log.Printf("✂️ %s %s: %d linhas de %s a %s", dataset, segment.label(), rows[i], manifest.Split.From, manifest.Split.To)
+	}
+	return nil
+}
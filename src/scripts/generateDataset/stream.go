@@ -0,0 +1,265 @@
+package generateDataset
+
+import (
+	"app/src/models"
+	"app/src/utils"
+	"bufio"
+	"bytes"
+	"container/heap"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// klineRow é uma linha do CSV de klines da Binance com os números já
+// convertidos. Cada linha é convertida uma única vez, na leitura.
+type klineRow struct {
+	OpenTime            int64
+	CloseTime           int64
+	Open                float64
+	High                float64
+	Low                 float64
+	Close               float64
+	Volume              float64
+	QuoteAssetVolume    float64
+	NumberOfTrades      int64
+	TakerBuyBaseVolume  float64
+	TakerBuyQuoteVolume float64
+}
+
+// Tamanho aproximado de um klineRow em memória
+const klineRowBytes = 11 * 8
+
+// Limites do buffer de leitura de cada arquivo de klines
+const (
+	maxReadBuffer = 64 * 1024
+	minReadBuffer = 4 * 1024
+)
+
+// parseKlineRow converte uma linha do CSV. Cabeçalhos e linhas incompletas retornam false.
+func parseKlineRow(line []byte) (klineRow, bool) {
+	var fields [12][]byte
+	n := 0
+	for n < len(fields) {
+		i := bytes.IndexByte(line, ',')
+		if i < 0 {
+			fields[n] = line
+			n++
+			break
+		}
+		fields[n] = line[:i]
+		line = line[i+1:]
+		n++
+	}
+	if n < 11 {
+		return klineRow{}, false
+	}
+
+	openTime, err := strconv.ParseInt(string(fields[0]), 10, 64)
+	if err != nil {
+		return klineRow{}, false
+	}
+	closeTime, _ := strconv.ParseInt(string(fields[6]), 10, 64)
+	trades, _ := strconv.ParseInt(string(fields[8]), 10, 64)
+
+	return klineRow{
+		OpenTime:            utils.NormalizeTimestampMs(openTime),
+		CloseTime:           utils.NormalizeTimestampMs(closeTime),
+		Open:                parseFloatBytes(fields[1]),
+		High:                parseFloatBytes(fields[2]),
+		Low:                 parseFloatBytes(fields[3]),
+		Close:               parseFloatBytes(fields[4]),
+		Volume:              parseFloatBytes(fields[5]),
+		QuoteAssetVolume:    parseFloatBytes(fields[7]),
+		NumberOfTrades:      trades,
+		TakerBuyBaseVolume:  parseFloatBytes(fields[9]),
+		TakerBuyQuoteVolume: parseFloatBytes(fields[10]),
+	}, true
+}
+
+func parseFloatBytes(field []byte) float64 {
+	value, err := strconv.ParseFloat(string(field), 64)
+	if err != nil {
+		return math.NaN()
+	}
+	return value
+}
+
+// candle converte o kline para o formato usado pelos indicadores
+func (k *klineRow) candle(symbol string) models.Candle {
+	return models.Candle{
+		Symbol:    symbol,
+		OpenTime:  k.OpenTime,
+		CloseTime: k.CloseTime,
+		Open:      k.Open,
+		High:      k.High,
+		Low:       k.Low,
+		Close:     k.Close,
+		Volume:    k.Volume,
+	}
+}
+
+// klineFile lê um CSV de klines linha a linha, mantendo apenas a linha atual em memória
+type klineFile struct {
+	coin    int
+	file    *os.File
+	scanner *bufio.Scanner
+	current klineRow
+}
+
+func openKlineFile(path string, coin, bufferSize int) (*klineFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, bufferSize), bufferSize)
+	return &klineFile{coin: coin, file: file, scanner: scanner}, nil
+}
+
+// next avança para o próximo kline válido do arquivo
+func (f *klineFile) next() (bool, error) {
+	for f.scanner.Scan() {
+		if k, ok := parseKlineRow(f.scanner.Bytes()); ok {
+			f.current = k
+			return true, nil
+		}
+	}
+	if err := f.scanner.Err(); err != nil {
+		return false, fmt.Errorf("erro ao ler %s: %w", f.file.Name(), err)
+	}
+	return false, nil
+}
+
+// klineHeap ordena os arquivos abertos pelo OpenTime do kline atual
+type klineHeap []*klineFile
+
+func (h klineHeap) Len() int { return len(h) }
+func (h klineHeap) Less(i, j int) bool {
+	if h[i].current.OpenTime == h[j].current.OpenTime {
+		return h[i].coin < h[j].coin
+	}
+	return h[i].current.OpenTime < h[j].current.OpenTime
+}
+func (h klineHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *klineHeap) Push(x any)   { *h = append(*h, x.(*klineFile)) }
+func (h *klineHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// mergedKlines são os klines de todas as cryptos em um mesmo OpenTime.
+// Present[c] indica se a crypto c tem kline nesse OpenTime.
+type mergedKlines struct {
+	OpenTime int64
+	Klines   []klineRow
+	Present  []bool
+}
+
+// klineMerger faz o k-way merge dos CSVs de klines de um dia, um por crypto,
+// entregando os klines agrupados por OpenTime em ordem cronológica. A memória
+// usada é um buffer de leitura por arquivo, independente do tamanho do dia.
+type klineMerger struct {
+	heap   klineHeap
+	merged mergedKlines
+}
+
+// newKlineMerger abre os arquivos de paths (na ordem das cryptos). Um arquivo inexistente é um erro.
+func newKlineMerger(paths []string, bufferSize int) (*klineMerger, error) {
+	m := &klineMerger{
+		merged: mergedKlines{
+			Klines:  make([]klineRow, len(paths)),
+			Present: make([]bool, len(paths)),
+		},
+	}
+	for coin, path := range paths {
+		f, err := openKlineFile(path, coin, bufferSize)
+		if err != nil {
+			m.Close()
+			return nil, err
+		}
+		ok, err := f.next()
+		if err != nil {
+			f.file.Close()
+			m.Close()
+			return nil, err
+		}
+		if !ok {
+			f.file.Close()
+			continue
+		}
+		heap.Push(&m.heap, f)
+	}
+	return m, nil
+}
+
+// Next retorna os klines do próximo OpenTime ou nil no fim dos arquivos. O
+// valor retornado é reaproveitado pela chamada seguinte.
+func (m *klineMerger) Next() (*mergedKlines, error) {
+	if m.heap.Len() == 0 {
+		return nil, nil
+	}
+
+	merged := &m.merged
+	merged.OpenTime = m.heap[0].current.OpenTime
+	for i := range merged.Present {
+		merged.Present[i] = false
+	}
+
+	for m.heap.Len() > 0 && m.heap[0].current.OpenTime == merged.OpenTime {
+		f := m.heap[0]
+		// Com OpenTime repetido no mesmo arquivo prevalece a última linha
+		merged.Klines[f.coin] = f.current
+		merged.Present[f.coin] = true
+
+		ok, err := f.next()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			heap.Fix(&m.heap, 0)
+		} else {
+			heap.Pop(&m.heap)
+			f.file.Close()
+		}
+	}
+	return merged, nil
+}
+
+func (m *klineMerger) Close() {
+	for _, f := range m.heap {
+		f.file.Close()
+	}
+	m.heap = nil
+}
+
+// streamKlineFile chama onKline para cada kline do arquivo, em ordem
+func streamKlineFile(path string, bufferSize int, onKline func(k *klineRow)) error {
+	f, err := openKlineFile(path, 0, bufferSize)
+	if err != nil {
+		return err
+	}
+	defer f.file.Close()
+
+	for {
+		ok, err := f.next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		onKline(&f.current)
+	}
+}
+
+// klineFilePath monta o caminho do CSV diário de klines de um par
+func klineFilePath(klineBasePath, cryptoPair, interval string, day time.Time) string {
+	return filepath.Join(klineBasePath, cryptoPair, interval, "csv", cryptoPair+"-"+interval+"-"+day.Format("2006-01-02")+".csv")
+}
@@ -0,0 +1,159 @@
+package getBinanceData
+
+import (
+	"app/src/binanceapi"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Número máximo de tentativas de um zip quando o download falha no meio (erro
+// de rede, corpo incompleto, .CHECKSUM indisponível) ou o SHA-256 não confere
+const maxDownloadAttempts = 3
+
+// Cliente dos arquivos do data.binance.vision, compartilhado pelos downloads
+var dataClient = binanceapi.New(binanceapi.DataConfig())
+
+// Resultado de um download de zip
+type downloadResult struct {
+	StatusCode int
+	SHA256     string
+	Verified   bool
+}
+
+// Status é a situação do zip registrada no manifesto
+func (r downloadResult) Status() string {
+	if r.Verified {
+		return statusVerified
+	}
+	return statusNoChecksum
+}
+
+// downloadVerifiedZip baixa o zip para zipPath e confere o SHA-256 com o arquivo
+// .CHECKSUM publicado ao lado dele. Falhas transitórias e hash divergente são
+// repetidos com a espera do cliente compartilhado; um status diferente de 200
+// no zip (ex: 404) é definitivo, pois o cliente já repetiu os 429, 418 e 5xx.
+func downloadVerifiedZip(url, zipPath string) (downloadResult, error) {
+	var result downloadResult
+	var lastErr error
+
+	for attempt := 1; attempt <= maxDownloadAttempts; attempt++ {
+		if attempt > 1 {
+			log.Printf("⚠️ %s (tentativa %d/%d): %v", url, attempt-1, maxDownloadAttempts, lastErr)
+			if err := dataClient.Backoff(context.Background(), attempt-2); err != nil {
+				return result, err
+			}
+		}
+		result = downloadResult{}
+
+		expected, err := fetchChecksum(url + ".CHECKSUM")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		resp, err := dataGet(url)
+		if err != nil {
+			lastErr = fmt.Errorf("erro ao baixar: %w", err)
+			continue
+		}
+
+		result.StatusCode = resp.StatusCode
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return result, fmt.Errorf("status %d", resp.StatusCode)
+		}
+
+		actual, err := saveWithHash(resp.Body, zipPath)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		result.SHA256 = actual
+
+		if expected == "" {
+			log.Printf("⚠️ CHECKSUM indisponível para %s, zip não verificado", url)
+			result.Verified = false
+			return result, nil
+		}
+
+		if strings.EqualFold(actual, expected) {
+			result.Verified = true
+			return result, nil
+		}
+
+		lastErr = fmt.Errorf("checksum divergente: esperado %s, obtido %s", expected, actual)
+		os.Remove(zipPath)
+	}
+
+	return result, fmt.Errorf("%w (após %d tentativas)", lastErr, maxDownloadAttempts)
+}
+
+// fetchChecksum lê o hash do arquivo .CHECKSUM ("<sha256>  <arquivo>").
+// Retorna string vazia quando a Binance não publica o arquivo.
+func fetchChecksum(checksumURL string) (string, error) {
+	resp, err := dataGet(checksumURL)
+	if err != nil {
+		return "", fmt.Errorf("erro ao baixar checksum: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status %d ao baixar checksum", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler checksum: %w", err)
+	}
+
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return "", fmt.Errorf("checksum inválido: %q", string(body))
+	}
+	return fields[0], nil
+}
+
+// saveWithHash grava o corpo em path (via arquivo .part) calculando o SHA-256
+func saveWithHash(body io.Reader, path string) (string, error) {
+	partPath := path + ".part"
+	file, err := os.Create(partPath)
+	if err != nil {
+		return "", fmt.Errorf("erro ao criar arquivo zip: %w", err)
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hash), body)
+	file.Close()
+	if err != nil {
+		os.Remove(partPath)
+		return "", fmt.Errorf("erro ao salvar arquivo zip: %w", err)
+	}
+
+	if err := os.Rename(partPath, path); err != nil {
+		os.Remove(partPath)
+		return "", fmt.Errorf("erro ao renomear arquivo zip: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// dataGet baixa url com o cliente compartilhado, que limita as requisições
+// simultâneas e espera antes de repetir após 429, 418, 5xx e erros de rede
+func dataGet(url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return dataClient.Do(req)
+}
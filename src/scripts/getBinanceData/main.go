@@ -2,12 +2,12 @@ package getBinanceData
 
 import (
 	"app/src/database"
+	"app/src/ledger"
 	"archive/zip"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -32,7 +32,11 @@ type EnabledCrypto struct {
 	IsEnabled  int
 }
 
-func Main(isAllCryptosEnabled bool) {
+// Main baixa os klines das criptos habilitadas (ou de todas, com
+// isAllCryptosEnabled). Com verifyExisting, todos os CSVs anteriores ao
+// manifesto são baixados novamente para conferir o checksum; sem ele, só os
+// incompletos.
+func Main(isAllCryptosEnabled bool, interval string, verifyExisting bool) {
 	// Configurar logging
 	log.SetFlags(log.LstdFlags | log.Lmsgprefix)
 	log.SetPrefix("INFO: ")
@@ -49,6 +53,25 @@ func Main(isAllCryptosEnabled bool) {
 		return
 	}
 
+	db, err := database.ConnectDatabase()
+	if err != nil {
+		log.Printf("Erro ao abrir o banco de dados: %v", err)
+		return
+	}
+	defer db.Close()
+
+	if _, err := database.Migrate(db); err != nil {
+		log.Printf("Erro ao aplicar migrações: %v", err)
+		return
+	}
+
+	// Tentativas de download anteriores (substitui o offline_links.txt)
+	downloads, err := ledger.Open(db, ledger.DefaultConfig(), os.Getenv("DATA_DIR"))
+	if err != nil {
+		log.Printf("Erro ao abrir o ledger de downloads: %v", err)
+		return
+	}
+
 	if len(cryptos) > 0 {
 		// Criar slice de pares de trading
 		var pairs []string
@@ -57,7 +80,7 @@ func Main(isAllCryptosEnabled bool) {
 		}
 
 		// Pega data inicial da última vez
-		startedDate := loadStartedDate()
+		startedDate := loadStartedDate(interval)
 		today := time.Now()
 		oneDayAgo := today.AddDate(0, 0, -1)
 
@@ -65,12 +88,14 @@ func Main(isAllCryptosEnabled bool) {
 		if startedDate.Before(oneDayAgo) {
 			log.Printf("📅 Recuperando dados recentes até: %s", startedDate.Format("2006-01-02"))
 			err := downloadAndExtractKlines(
+				downloads,
 				pairs,
-				"1m", // Intervalo de 1 minuto
+				interval,
 				0,
 				startedDate.Format("2006-01-02"),
 				oneDayAgo.Format("2006-01-02"),
 				os.Getenv("DATA_DIR"),
+				verifyExisting,
 			)
 			if err != nil {
 				log.Printf("Erro ao baixar dados recentes: %v", err)
@@ -78,14 +103,16 @@ func Main(isAllCryptosEnabled bool) {
 		}
 
 		// Segunda parte: histórico completo até 2017
-		lastProcessed := loadLastProcessedDate()
+		lastProcessed := loadLastProcessedDate(interval)
 		err := downloadAndExtractKlines(
+			downloads,
 			pairs,
-			"1m", // Intervalo de 1 minuto
+			interval,
 			0,
 			"2017-01-01",
 			lastProcessed.Format("2006-01-02"),
 			os.Getenv("DATA_DIR"),
+			verifyExisting,
 		)
 		if err != nil {
 			log.Printf("Erro ao baixar dados históricos: %v", err)
@@ -160,9 +187,31 @@ func getAllCryptos() ([]EnabledCrypto, error) {
 	return cryptos, nil
 }
 
-// Salvar progresso em arquivo JSON
-func saveProgressData(lastProcessedDate, startedDate *time.Time) error {
-	prrogressFile := os.Getenv("DATA_DIR") + "/progress.json"
+// progressFile é o arquivo de progresso do intervalo. O progress.json usado
+// antes de cada intervalo ter o seu era sempre do download em 1m e é renomeado
+// para progress_1m.json.
+func progressFile(interval string) string {
+	dataDir := os.Getenv("DATA_DIR")
+	path := filepath.Join(dataDir, fmt.Sprintf("progress_%s.json", interval))
+
+	legacy := filepath.Join(dataDir, "progress.json")
+	if interval == "1m" {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if _, err := os.Stat(legacy); err == nil {
+				if err := os.Rename(legacy, path); err != nil {
+					log.Printf("⚠️ Erro ao renomear %s: %v", legacy, err)
+				} else {
+					log.Printf("📌 %s renomeado para %s", legacy, path)
+				}
+			}
+		}
+	}
+	return path
+}
+
+// Salvar progresso do intervalo em arquivo JSON
+func saveProgressData(interval string, lastProcessedDate, startedDate *time.Time) error {
+	prrogressFile := progressFile(interval)
 
 	// Garantir que o diretório data existe
 	if err := os.MkdirAll(filepath.Dir(prrogressFile), 0755); err != nil {
@@ -200,9 +249,9 @@ func saveProgressData(lastProcessedDate, startedDate *time.Time) error {
 	return nil
 }
 
-// Carregar a última data processada
-func loadLastProcessedDate() time.Time {
-	prrogressFile := os.Getenv("DATA_DIR") + "/progress.json"
+// Carregar a última data processada do intervalo
+func loadLastProcessedDate(interval string) time.Time {
+	prrogressFile := progressFile(interval)
 
 	if _, err := os.Stat(prrogressFile); err == nil {
 		file, err := os.ReadFile(prrogressFile)
@@ -224,9 +273,9 @@ func loadLastProcessedDate() time.Time {
 	return time.Now().AddDate(0, 0, -1)
 }
 
-// Carregar a data de início do download
-func loadStartedDate() time.Time {
-	prrogressFile := os.Getenv("DATA_DIR") + "/progress.json"
+// Carregar a data de início do download do intervalo
+func loadStartedDate(interval string) time.Time {
+	prrogressFile := progressFile(interval)
 
 	if _, err := os.Stat(prrogressFile); err == nil {
 		file, err := os.ReadFile(prrogressFile)
@@ -245,7 +294,7 @@ func loadStartedDate() time.Time {
 }
 
 // Download e extração de arquivos Klines da Binance
-func downloadAndExtractKlines(pairs []string, interval string, daysToProcess int, minDate, maxDate string, saveDir string) error {
+func downloadAndExtractKlines(downloads *ledger.Ledger, pairs []string, interval string, daysToProcess int, minDate, maxDate string, saveDir string, verifyExisting bool) error {
 	// Definir maxDate se não fornecido
 	if maxDate == "" {
 		maxDate = time.Now().Format("2006-01-02")
@@ -263,16 +312,47 @@ func downloadAndExtractKlines(pairs []string, interval string, daysToProcess int
 		return fmt.Errorf("formato de data mínima inválido: %w", err)
 	}
 
+	// Arquivos já baixados, com ou sem checksum
+	manifest, err := loadManifest(saveDir, verifyExisting)
+	if err != nil {
+		return err
+	}
+
 	// Salvar a data de início do download
-	if err := saveProgressData(nil, &currentDate); err != nil {
+	if err := saveProgressData(interval, nil, &currentDate); err != nil {
 		log.Printf("Erro ao salvar data de início: %v", err)
 	}
 
 	// Contador de dias processados
 	daysProcessed := 0
 
+	// Meses anteriores ao atual já estão completos e têm arquivo mensal
+	now := time.Now().UTC()
+	currentMonthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
 	// Processar enquanto não atingir o limite de dias ou a data mínima
 	for (daysToProcess == 0 || daysProcessed < daysToProcess) && !currentDate.Before(minDateTime) {
+		monthStart := time.Date(currentDate.Year(), currentDate.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if monthStart.Before(currentMonthStart) {
+			firstDay := monthStart
+			if firstDay.Before(minDateTime) {
+				firstDay = minDateTime
+			}
+
+			downloadMonth(manifest, downloads, pairs, interval, firstDay, currentDate, saveDir)
+
+			// Salvar o progresso no primeiro dia do mês processado
+			if err := saveProgressData(interval, &firstDay, nil); err != nil {
+				log.Printf("Erro ao salvar progresso: %v", err)
+			}
+
+			daysProcessed += int(currentDate.Sub(firstDay).Hours()/24) + 1
+			currentDate = firstDay.AddDate(0, 0, -1)
+
+			log.Printf("📅 Processado mês: %s (%d dias)", monthStart.Format("2006-01"), daysProcessed)
+			continue
+		}
+
 		year := currentDate.Year()
 		month := currentDate.Month()
 		day := currentDate.Day()
@@ -282,11 +362,10 @@ func downloadAndExtractKlines(pairs []string, interval string, daysToProcess int
 
 		if stopGoroutines {
 			for _, symbol := range pairs {
-				downloadAndExtractKlineForSymbol(totalPairs, symbol, interval, year, month, day, saveDir, &stopGoroutines, nil)
+				downloadAndExtractKlineForSymbol(manifest, downloads, totalPairs, symbol, interval, year, month, day, saveDir, &stopGoroutines)
 			}
 		} else {
 			var wg sync.WaitGroup
-			var mu sync.Mutex
 
 			maxGoroutines := runtime.NumCPU() * 2
 			sem := make(chan struct{}, maxGoroutines)
@@ -297,14 +376,14 @@ func downloadAndExtractKlines(pairs []string, interval string, daysToProcess int
 				go func(symbol string) {
 					defer wg.Done()
 					defer func() { <-sem }()
-					downloadAndExtractKlineForSymbol(totalPairs, symbol, interval, year, month, day, saveDir, &stopGoroutines, &mu)
+					downloadAndExtractKlineForSymbol(manifest, downloads, totalPairs, symbol, interval, year, month, day, saveDir, &stopGoroutines)
 				}(symbol)
 			}
 			wg.Wait()
 		}
 
 		// Salvar o progresso atual antes de ir para o próximo dia
-		if err := saveProgressData(&currentDate, nil); err != nil {
+		if err := saveProgressData(interval, &currentDate, nil); err != nil {
 			log.Printf("Erro ao salvar progresso: %v", err)
 		}
 
@@ -319,8 +398,37 @@ func downloadAndExtractKlines(pairs []string, interval string, daysToProcess int
 	return nil
 }
 
-func downloadAndExtractKlineForSymbol(totalPairs int, symbol, interval string, year int, month time.Month, day int, saveDir string, stopGorotines *bool, mu *sync.Mutex) {
-	baseURL := "https://data.binance.vision/data/spot/daily/klines"
+// downloadMonth baixa o intervalo de firstDay a lastDay (mesmo mês) de cada par
+// pelo arquivo mensal, usando os arquivos diários quando o mensal não existe.
+func downloadMonth(manifest *verifiedManifest, downloads *ledger.Ledger, pairs []string, interval string, firstDay, lastDay time.Time, saveDir string) {
+	var wg sync.WaitGroup
+
+	maxGoroutines := runtime.NumCPU() * 2
+	sem := make(chan struct{}, maxGoroutines)
+
+	for _, symbol := range pairs {
+		wg.Add(1)
+		sem <- struct{}{} // bloquear aqui se já tiver maxGoroutines em execução
+		go func(symbol string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if downloadAndExtractMonthlyKlineForSymbol(manifest, downloads, symbol, interval, firstDay, lastDay, saveDir) {
+				return
+			}
+
+			// Fallback: um arquivo por dia
+			stopGoroutines := false
+			for d := lastDay; !d.Before(firstDay); d = d.AddDate(0, 0, -1) {
+				downloadAndExtractKlineForSymbol(manifest, downloads, len(pairs), symbol, interval, d.Year(), d.Month(), d.Day(), saveDir, &stopGoroutines)
+			}
+		}(symbol)
+	}
+	wg.Wait()
+}
+
+func downloadAndExtractKlineForSymbol(manifest *verifiedManifest, downloads *ledger.Ledger, totalPairs int, symbol, interval string, year int, month time.Month, day int, saveDir string, stopGorotines *bool) {
+	baseURL := dataClient.URL("/data/spot/daily/klines")
 	zipDir := filepath.Join(saveDir+"/data.binance.vision/data/spot/daily/klines", symbol, interval, "zip")
 	csvDir := filepath.Join(saveDir+"/data.binance.vision/data/spot/daily/klines", symbol, interval, "csv")
 
@@ -341,13 +449,18 @@ func downloadAndExtractKlineForSymbol(totalPairs int, symbol, interval string, y
 	zipPath := filepath.Join(zipDir, fileName)
 	csvFilePath := filepath.Join(csvDir, fileName[:len(fileName)-4]+".csv")
 
-	// Verificar se o arquivo CSV já existe
-	if _, err := os.Stat(csvFilePath); err == nil {
+	// Verificar se o arquivo CSV já existe (no manifesto ou baixado antes dele)
+	if manifest.present(csvFilePath, interval) {
 		*stopGorotines = false
 		return
 	}
+	if _, err := os.Stat(csvFilePath); err == nil {
+		log.Printf("⚠️ CSV anterior ao manifesto incompleto ou não conferido, baixando novamente: %s", csvFilePath)
+	}
 
-	if isOfflineLink(url) {
+	if skip, err := downloads.Skip(url); err != nil {
+		log.Printf("⚠️ %v", err)
+	} else if skip {
 		*stopGorotines = false
 		log.Printf("Link offline: %s", url)
 		return
@@ -357,53 +470,33 @@ func downloadAndExtractKlineForSymbol(totalPairs int, symbol, interval string, y
 	log.Printf("⬇️ Baixando: %s", url)
 
 	// Fazer o download do arquivo
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	if mu != nil {
-		mu.Lock()
-	}
-	resp, err := client.Get(url)
-	time.Sleep(1 * time.Second) // Aguardar um segundo antes de continuar
-	if mu != nil {
-		mu.Unlock()
-	}
+	result, err := downloadVerifiedZip(url, zipPath)
 	if err != nil {
 		log.Printf("⚠️ Erro ao baixar %s: %v", fileName, err)
-		insertOfflineLink(url)
+		if err := downloads.Record(url, result.StatusCode, err, ""); err != nil {
+			log.Printf("⚠️ %v", err)
+		}
 		return
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("❌ Arquivo não encontrado: %s (status %d)", fileName, resp.StatusCode)
-		resp.Body.Close()
-		insertOfflineLink(url)
+	// Extrair o ZIP
+	if err := extractZip(zipPath, csvDir); err != nil {
+		log.Printf("❌ Erro ao extrair %s: %v", zipPath, err)
+		if err := downloads.Record(url, result.StatusCode, err, ""); err != nil {
+			log.Printf("⚠️ %v", err)
+		}
 		return
 	}
 
-	// Criar arquivo zip
-	zipFile, err := os.Create(zipPath)
-	if err != nil {
-		log.Printf("❌ Erro ao criar arquivo zip: %v", err)
-		resp.Body.Close()
-		return
+	if err := manifest.add(csvFilePath, url, result.SHA256, result.Status()); err != nil {
+		log.Printf("⚠️ Erro ao registrar %s no manifesto: %v", csvFilePath, err)
 	}
-
-	// Copiar conteúdo do response para o arquivo
-	_, err = io.Copy(zipFile, resp.Body)
-	resp.Body.Close()
-	zipFile.Close()
-
-	if err != nil {
-		log.Printf("❌ Erro ao salvar arquivo zip: %v", err)
-		return
+	sha := ""
+	if result.Verified {
+		sha = result.SHA256
 	}
-
-	// Extrair o ZIP
-	if err := extractZip(zipPath, csvDir); err != nil {
-		log.Printf("❌ Erro ao extrair %s: %v", zipPath, err)
-		return
+	if err := downloads.Record(url, result.StatusCode, nil, sha); err != nil {
+		log.Printf("⚠️ %v", err)
 	}
 
 	log.Printf("📦 Extraído para: %s", csvDir)
@@ -434,7 +527,8 @@ func extractZip(zipPath, destDir string) error {
 			os.MkdirAll(path, 0755)
 		} else {
 			os.MkdirAll(filepath.Dir(path), 0755)
-			outFile, err := os.Create(path)
+			// Extrai para .tmp e renomeia, para nunca deixar um CSV parcial
+			outFile, err := os.Create(path + ".tmp")
 			if err != nil {
 				rc.Close()
 				return fmt.Errorf("erro ao criar arquivo de saída: %w", err)
@@ -444,99 +538,16 @@ func extractZip(zipPath, destDir string) error {
 			outFile.Close()
 			if err != nil {
 				rc.Close()
+				os.Remove(path + ".tmp")
 				return fmt.Errorf("erro ao copiar conteúdo: %w", err)
 			}
+			if err := os.Rename(path+".tmp", path); err != nil {
+				rc.Close()
+				return fmt.Errorf("erro ao renomear arquivo extraído: %w", err)
+			}
 		}
 		rc.Close()
 	}
 
 	return nil
 }
-
-func isOfflineLink(link string) bool {
-	offlineFile := os.Getenv("DATA_DIR") + "/offline_links.txt"
-	// Verifica se o diretório existe, se não, cria
-	if err := os.MkdirAll(filepath.Dir(offlineFile), 0755); err != nil {
-		log.Printf("Erro ao criar diretório para offline_links.txt: %v", err)
-		return false
-	}
-	// Tenta abrir o arquivo, se não existir, cria
-	if _, err := os.Stat(offlineFile); os.IsNotExist(err) {
-		file, err := os.Create(offlineFile)
-		if err != nil {
-			log.Printf("Erro ao criar offline_links.txt: %v", err)
-			return false
-		}
-		file.Close()
-	}
-
-	// Se o arquivo existir, verifica se alguma linha contém o link
-	content, err := os.ReadFile(offlineFile)
-	if err != nil {
-		log.Printf("Erro ao ler offline_links.txt: %v", err)
-		return false
-	}
-	lines := string(content)
-	if lines != "" {
-		for _, line := range splitLines(lines) {
-			if line != "" && contains(line, link) {
-				return true
-			}
-		}
-	}
-	return false
-}
-
-// contains verifica se substr está contido em s
-func contains(s, substr string) bool {
-	return len(substr) > 0 && len(s) >= len(substr) && (s == substr || (len(s) > len(substr) && (len(s) > 0 && len(substr) > 0 && (len(s) >= len(substr) && (func() bool {
-		for i := 0; i <= len(s)-len(substr); i++ {
-			if s[i:i+len(substr)] == substr {
-				return true
-			}
-		}
-		return false
-	}())))))
-}
-
-// splitLines divide uma string em linhas, suportando \n e \r\n
-func splitLines(s string) []string {
-	var lines []string
-	start := 0
-	for i := 0; i < len(s); i++ {
-		if s[i] == '\n' {
-			line := s[start:i]
-			if len(line) > 0 && line[len(line)-1] == '\r' {
-				line = line[:len(line)-1]
-			}
-			lines = append(lines, line)
-			start = i + 1
-		}
-	}
-	if start < len(s) {
-		lines = append(lines, s[start:])
-	}
-	return lines
-}
-
-func insertOfflineLink(link string) {
-	offlineFile := os.Getenv("DATA_DIR") + "/offline_links.txt"
-
-	// Verifica se o diretório existe, se não, cria
-	if err := os.MkdirAll(filepath.Dir(offlineFile), 0755); err != nil {
-		log.Printf("Erro ao criar diretório para offline_links.txt: %v", err)
-		return
-	}
-
-	// Abre o arquivo offline_links.txt para adicionar o link
-	file, err := os.OpenFile(offlineFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		log.Printf("Erro ao abrir offline_links.txt: %v", err)
-		return
-	}
-	defer file.Close()
-
-	if _, err := file.WriteString(link + "\n"); err != nil {
-		log.Printf("Erro ao escrever no arquivo offline_links.txt: %v", err)
-	}
-}
@@ -0,0 +1,220 @@
+package getBinanceData
+
+import (
+	"app/src/utils"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Situação do zip de origem de um CSV no manifesto
+const (
+	statusVerified = "verified"
+	// A Binance não publicou o .CHECKSUM do zip
+	statusNoChecksum = "no_checksum"
+	// CSV baixado antes do manifesto, conferido só pelas linhas completas
+	statusExisting = "existing"
+)
+
+// Colunas de uma linha de kline do data.binance.vision
+const klineColumns = 12
+
+// Registro de um CSV extraído de um zip baixado. SHA256 é o hash calculado do
+// zip, conferido com o .CHECKSUM quando Status é statusVerified.
+type manifestEntry struct {
+	File       string `json:"file"`
+	URL        string `json:"url"`
+	SHA256     string `json:"sha256"`
+	Status     string `json:"status,omitempty"`
+	VerifiedAt string `json:"verified_at"`
+}
+
+// verifiedManifest mantém em memória os arquivos baixados e grava cada novo
+// registro em DATA_DIR/data.binance.vision/manifest.jsonl (uma linha por arquivo).
+type verifiedManifest struct {
+	mu      sync.Mutex
+	path    string
+	baseDir string
+	entries map[string]manifestEntry
+	// Baixa novamente os CSVs anteriores ao manifesto em vez de mantê-los
+	// quando têm todas as linhas do dia
+	verifyExisting bool
+}
+
+func loadManifest(saveDir string, verifyExisting bool) (*verifiedManifest, error) {
+	baseDir := filepath.Join(saveDir, "data.binance.vision")
+	m := &verifiedManifest{
+		path:           filepath.Join(baseDir, "manifest.jsonl"),
+		baseDir:        baseDir,
+		entries:        make(map[string]manifestEntry),
+		verifyExisting: verifyExisting,
+	}
+
+	file, err := os.Open(m.path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir manifesto: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry manifestEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// Linha truncada por uma execução interrompida
+			continue
+		}
+		// Registros anteriores ao campo status eram sempre verificados
+		if entry.Status == "" {
+			entry.Status = statusVerified
+		}
+		m.entries[entry.File] = entry
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler manifesto: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, entry := range m.entries {
+		counts[entry.Status]++
+	}
+	log.Printf("📒 Manifesto carregado: %d arquivos verificados, %d sem checksum, %d anteriores ao manifesto",
+		counts[statusVerified], counts[statusNoChecksum], counts[statusExisting])
+	return m, nil
+}
+
+func (m *verifiedManifest) key(csvPath string) string {
+	rel, err := filepath.Rel(m.baseDir, csvPath)
+	if err != nil {
+		return filepath.ToSlash(csvPath)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// contains indica se o CSV foi registrado no manifesto, com ou sem checksum
+func (m *verifiedManifest) contains(csvPath string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.entries[m.key(csvPath)]
+	return ok
+}
+
+// status retorna a situação do CSV no manifesto ("" quando não registrado)
+func (m *verifiedManifest) status(csvPath string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.entries[m.key(csvPath)].Status
+}
+
+// present indica se o CSV existe e não precisa ser baixado novamente. Um CSV
+// baixado antes do manifesto só é mantido quando tem todas as linhas do dia
+// completas, e é então registrado como statusExisting para não ser lido de
+// novo. Com verifyExisting, esses CSVs são sempre baixados novamente.
+func (m *verifiedManifest) present(csvPath, interval string) bool {
+	if _, err := os.Stat(csvPath); err != nil {
+		return false
+	}
+
+	switch m.status(csvPath) {
+	case statusVerified, statusNoChecksum:
+		return true
+	case statusExisting:
+		return !m.verifyExisting
+	}
+	if m.verifyExisting {
+		return false
+	}
+
+	complete, err := csvComplete(csvPath, interval)
+	if err != nil {
+		log.Printf("⚠️ Erro ao ler %s: %v", csvPath, err)
+		return false
+	}
+	if !complete {
+		return false
+	}
+	if err := m.add(csvPath, "", "", statusExisting); err != nil {
+		log.Printf("⚠️ Erro ao registrar %s no manifesto: %v", csvPath, err)
+	}
+	return true
+}
+
+// csvComplete indica se o CSV diário tem um candle por intervalo do dia (ao
+// menos um nos intervalos maiores que um dia) e nenhuma linha truncada
+func csvComplete(csvPath, interval string) (bool, error) {
+	file, err := os.Open(csvPath)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	rows := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) != klineColumns {
+			return false, nil
+		}
+		// Ignora o cabeçalho
+		if _, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+			rows++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, err
+	}
+
+	if expected := utils.RowsPerDay(interval); expected > 0 {
+		return rows == expected, nil
+	}
+	return rows > 0, nil
+}
+
+// add registra o CSV extraído do zip baixado de url com a situação status
+func (m *verifiedManifest) add(csvPath, url, sha256, status string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := manifestEntry{
+		File:       m.key(csvPath),
+		URL:        url,
+		SHA256:     sha256,
+		Status:     status,
+		VerifiedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return fmt.Errorf("erro ao criar diretório do manifesto: %w", err)
+	}
+	file, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir manifesto: %w", err)
+	}
+	defer file.Close()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("erro ao gravar manifesto: %w", err)
+	}
+
+	m.entries[entry.File] = entry
+	return nil
+}
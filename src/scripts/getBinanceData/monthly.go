@@ -0,0 +1,205 @@
+package getBinanceData
+
+import (
+	"app/src/ledger"
+	"app/src/utils"
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Caminho do CSV diário esperado pelo generateDataset
+func dailyCSVPath(saveDir, symbol, interval string, date time.Time) string {
+	csvDir := filepath.Join(saveDir+"/data.binance.vision/data/spot/daily/klines", symbol, interval, "csv")
+	return filepath.Join(csvDir, fmt.Sprintf("%s-%s-%s.csv", symbol, interval, date.Format("2006-01-02")))
+}
+
+// downloadAndExtractMonthlyKlineForSymbol baixa o zip mensal de klines e o divide
+// nos mesmos CSVs diários gerados pelo download diário. Cobre os dias de
+// firstDay até lastDay (inclusive), que devem estar no mesmo mês. Retorna false
+// quando o arquivo mensal não está disponível, para que o chamador use os diários.
+func downloadAndExtractMonthlyKlineForSymbol(manifest *verifiedManifest, downloads *ledger.Ledger, symbol, interval string, firstDay, lastDay time.Time, saveDir string) bool {
+	baseURL := dataClient.URL("/data/spot/monthly/klines")
+	zipDir := filepath.Join(saveDir+"/data.binance.vision/data/spot/monthly/klines", symbol, interval, "zip")
+	csvDir := filepath.Join(saveDir+"/data.binance.vision/data/spot/monthly/klines", symbol, interval, "csv")
+
+	monthStr := firstDay.Format("2006-01")
+	fileName := fmt.Sprintf("%s-%s-%s.zip", symbol, interval, monthStr)
+	url := fmt.Sprintf("%s/%s/%s/%s", baseURL, symbol, interval, fileName)
+	zipPath := filepath.Join(zipDir, fileName)
+	monthlyCSVPath := filepath.Join(csvDir, fileName[:len(fileName)-4]+".csv")
+
+	// Mês já dividido anteriormente
+	if manifest.contains(monthlyCSVPath) {
+		return true
+	}
+
+	// Todos os dias do intervalo já foram baixados pelo fluxo diário
+	complete := true
+	for d := firstDay; !d.After(lastDay); d = d.AddDate(0, 0, 1) {
+		if !manifest.present(dailyCSVPath(saveDir, symbol, interval, d), interval) {
+			complete = false
+			break
+		}
+	}
+	if complete {
+		return true
+	}
+
+	if skip, err := downloads.Skip(url); err != nil {
+		log.Printf("⚠️ %v", err)
+	} else if skip {
+		return false
+	}
+
+	if err := os.MkdirAll(zipDir, 0755); err != nil {
+		log.Printf("Erro ao criar diretório zip: %v", err)
+		return false
+	}
+	if err := os.MkdirAll(csvDir, 0755); err != nil {
+		log.Printf("Erro ao criar diretório csv: %v", err)
+		return false
+	}
+
+	log.Printf("⬇️ Baixando mensal: %s", url)
+
+	result, err := downloadVerifiedZip(url, zipPath)
+	if err != nil {
+		log.Printf("⚠️ Mensal indisponível %s: %v", fileName, err)
+		if err := downloads.Record(url, result.StatusCode, err, ""); err != nil {
+			log.Printf("⚠️ %v", err)
+		}
+		return false
+	}
+
+	if err := extractZip(zipPath, csvDir); err != nil {
+		log.Printf("❌ Erro ao extrair %s: %v", zipPath, err)
+		if err := downloads.Record(url, result.StatusCode, err, ""); err != nil {
+			log.Printf("⚠️ %v", err)
+		}
+		return false
+	}
+	os.Remove(zipPath)
+
+	days, err := splitMonthlyCSV(monthlyCSVPath, symbol, interval, saveDir)
+	if err != nil {
+		log.Printf("❌ Erro ao dividir %s em arquivos diários: %v", monthlyCSVPath, err)
+		if err := downloads.Record(url, result.StatusCode, err, ""); err != nil {
+			log.Printf("⚠️ %v", err)
+		}
+		return false
+	}
+
+	for _, dayPath := range days {
+		if err := manifest.add(dayPath, url, result.SHA256, result.Status()); err != nil {
+			log.Printf("⚠️ Erro ao registrar %s no manifesto: %v", dayPath, err)
+		}
+	}
+	if err := manifest.add(monthlyCSVPath, url, result.SHA256, result.Status()); err != nil {
+		log.Printf("⚠️ Erro ao registrar %s no manifesto: %v", monthlyCSVPath, err)
+	}
+	sha := ""
+	if result.Verified {
+		sha = result.SHA256
+	}
+
+	if err := downloads.Record(url, result.StatusCode, nil, sha); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+
+	// Os diários já contêm todas as linhas do mês
+	if err := os.Remove(monthlyCSVPath); err != nil {
+		log.Printf("⚠️ Erro ao remover CSV mensal: %v", err)
+	}
+
+	log.Printf("📦 %s dividido em %d arquivos diários", fileName, len(days))
+	return true
+}
+
+// splitMonthlyCSV copia cada linha do CSV mensal para o CSV diário do dia do
+// seu OpenTime (UTC) e retorna os caminhos gerados.
+func splitMonthlyCSV(monthlyCSVPath, symbol, interval, saveDir string) ([]string, error) {
+	source, err := os.Open(monthlyCSVPath)
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+
+	type dayFile struct {
+		path   string
+		file   *os.File
+		writer *bufio.Writer
+	}
+	files := make(map[string]*dayFile)
+	var order []string
+
+	// Em caso de erro, descarta os .tmp ainda não renomeados
+	closeAll := func() {
+		for _, f := range files {
+			f.file.Close()
+			os.Remove(f.path + ".tmp")
+		}
+	}
+
+	scanner := bufio.NewScanner(source)
+	for scanner.Scan() {
+		line := scanner.Text()
+		openTimeStr, _, _ := strings.Cut(line, ",")
+		openTime, err := strconv.ParseInt(openTimeStr, 10, 64)
+		if err != nil {
+			// Cabeçalho ou linha inválida
+			continue
+		}
+
+		day := time.UnixMilli(utils.NormalizeTimestampMs(openTime)).UTC()
+		key := day.Format("2006-01-02")
+
+		f, ok := files[key]
+		if !ok {
+			path := dailyCSVPath(saveDir, symbol, interval, day)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				closeAll()
+				return nil, err
+			}
+			file, err := os.Create(path + ".tmp")
+			if err != nil {
+				closeAll()
+				return nil, err
+			}
+			f = &dayFile{path: path, file: file, writer: bufio.NewWriter(file)}
+			files[key] = f
+			order = append(order, key)
+		}
+
+		if _, err := f.writer.WriteString(line + "\n"); err != nil {
+			closeAll()
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		closeAll()
+		return nil, err
+	}
+
+	var paths []string
+	for _, key := range order {
+		f := files[key]
+		if err := f.writer.Flush(); err != nil {
+			closeAll()
+			return nil, err
+		}
+		f.file.Close()
+		if err := os.Rename(f.path+".tmp", f.path); err != nil {
+			closeAll()
+			return nil, err
+		}
+		paths = append(paths, f.path)
+	}
+
+	return paths, nil
+}
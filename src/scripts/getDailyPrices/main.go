@@ -1,25 +1,53 @@
 package getDailyPrices
 
 import (
+	"app/src/binanceapi"
 	"app/src/database"
 	"app/src/models"
 	"app/src/utils"
+	"context"
 	"database/sql"
 	"encoding/csv"
-	"encoding/json"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
-	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
 	_ "modernc.org/sqlite"
 )
 
-func Main() {
+// Config do GetBinanceCurrentDayCryptos
+type Config struct {
+	// Intervalo dos klines
+	Interval string
+	// Também exporta o histórico do dia em DATA_DIR/last_history/<intervalo>/<SYMBOL>.csv
+	CSV bool
+}
+
+// DefaultConfig busca klines de 1 minuto e grava apenas no banco de dados
+func DefaultConfig() Config {
+	return Config{Interval: "1m"}
+}
+
+// Criptomoeda habilitada na Binance com os IDs usados no price_history
+type enabledCrypto struct {
+	ID         int
+	ExchangeID int
+	Symbol     string
+}
+
+// Main busca os klines do dia corrente das criptomoedas habilitadas e grava no
+// price_history (e no CSV com config.CSV)
+func Main(config Config) {
+	interval := config.Interval
+	intervalDuration, err := utils.IntervalDuration(interval)
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return
+	}
+
 	// Abrir conexão com o banco de dados SQLite
 	db, err := database.ConnectDatabase()
 	if err != nil {
@@ -27,7 +55,12 @@ func Main() {
 	}
 	defer db.Close()
 
-	// Exemplo: Buscar criptomoedas da Binance habilitadas
+	if _, err := database.Migrate(db); err != nil {
+		log.Printf("Erro ao garantir tabelas: %v", err)
+		return
+	}
+
+	// Buscar criptomoedas da Binance habilitadas
 	cryptos, err := fetchCryptos(db)
 	if err != nil {
 		log.Printf("Erro ao buscar criptomoedas: %v", err)
@@ -35,24 +68,37 @@ func Main() {
 		log.Printf("%d Criptomoedas encontradas!", len(cryptos))
 	}
 
+	client := binanceapi.New(binanceapi.DefaultConfig())
 	priceHistoryMap := make(map[string][]models.BinancePriceHistory)
 
 	start := utils.StartOfCurrentDayUTC()
 
-	for i := start; i.Add(time.Hour).Before(time.Now().UTC()); i = i.Add(time.Hour) {
+	// Cada requisição busca até 60 candles (uma hora em 1m)
+	window := 60 * intervalDuration
+
+	for i := start; i.Before(time.Now().UTC()); i = i.Add(window) {
 		startTime := i
-		endTime := i.Add(time.Hour)
+		endTime := i.Add(window)
+		if now := time.Now().UTC(); endTime.After(now) {
+			endTime = now
+		}
 
-		for _, symbol := range cryptos {
+		for _, crypto := range cryptos {
+			symbol := crypto.Symbol
 			priceHistoryList := priceHistoryMap[symbol]
 
-			klines, err := fetchBinanceKlines(symbol, startTime, endTime)
+			klines, err := client.Klines(context.Background(), symbol+"USDT", interval, startTime.UnixMilli(), endTime.UnixMilli(), 60)
 			if err != nil {
 				log.Printf("Erro ao buscar klines da Binance para %s: %v", symbol, err)
 				continue
 			}
 
 			for _, kline := range klines {
+				// Ignora o candle ainda em formação
+				if kline.CloseTime >= time.Now().UTC().UnixMilli() {
+					continue
+				}
+
 				date := time.UnixMilli(kline.CloseTime)
 				date = date.Truncate(time.Minute)
 				openPrice, _ := strconv.ParseFloat(kline.Open, 64)
@@ -67,8 +113,8 @@ func Main() {
 				priceHistoryList = append(priceHistoryList, models.BinancePriceHistory{
 					Date:                    date, // ou time.Now() se preferir
 					Price:                   closePrice,
-					CryptoID:                1, // ajuste conforme necessário
-					ExchangeID:              1, // ajuste conforme necessário
+					CryptoID:                crypto.ID,
+					ExchangeID:              crypto.ExchangeID,
 					OpenTime:                kline.OpenTime,
 					OpenPrice:               openPrice,
 					HighPrice:               highPrice,
@@ -89,98 +135,88 @@ func Main() {
 		log.Printf("UTC Agora: %s", time.Now().UTC().Format(time.RFC3339))
 	}
 
-	for _, symbol := range cryptos {
-		priceHistoryList := priceHistoryMap[symbol]
-		err = savePriceHistoryToCSV(symbol, priceHistoryList)
-		if err != nil {
-			log.Printf("Erro ao inserir histórico de preços: %v", err)
+	saved := 0
+	for _, crypto := range cryptos {
+		priceHistoryList := priceHistoryMap[crypto.Symbol]
+		if err := savePriceHistory(db, interval, priceHistoryList); err != nil {
+			log.Printf("Erro ao inserir histórico de preços de %s: %v", crypto.Symbol, err)
+			continue
 		}
-	}
-}
-
-// Retorna o histórico de preços da API da Binance para a criptomoeda symbol
-func fetchBinanceKlines(symbol string, startTime time.Time, endTime time.Time) ([]models.BinanceKline, error) {
-	// Define a URL da API para buscar o histórico de Klines (ex: 1 minuto)
-	symbolParam := symbol + "USDT"
-
-	startTimeStr := fmt.Sprintf("%d", startTime.UnixMilli())
-	endTimeStr := fmt.Sprintf("%d", endTime.UnixMilli())
-
-	baseURL := "https://api.binance.com/api/v3/klines"
-	u, _ := url.Parse(baseURL)
-
-	query := url.Values{}
-	query.Set("symbol", symbolParam)
-	query.Set("interval", "1m")
-	query.Set("limit", "60")
-	query.Set("startTime", startTimeStr)
-	query.Set("endTime", endTimeStr)
-
-	u.RawQuery = query.Encode()
-	finalURL := u.String()
+		saved += len(priceHistoryList)
 
-	// Faz a requisição HTTP
-	resp, err := http.Get(finalURL)
-	if err != nil {
-		return nil, fmt.Errorf("erro ao fazer requisição para Binance: %w", err)
+		if config.CSV {
+			if err := savePriceHistoryToCSV(crypto.Symbol, interval, priceHistoryList); err != nil {
+				log.Printf("Erro ao exportar histórico de preços de %s: %v", crypto.Symbol, err)
+			}
+		}
 	}
-	defer resp.Body.Close()
+	log.Printf("💾 %d klines de %s gravados no price_history", saved, interval)
+}
 
-	// Verifica se o status HTTP é 200
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("resposta inválida da Binance: %s", resp.Status)
+// savePriceHistory grava os klines no price_history em uma transação. Klines
+// já gravados (mesma crypto, exchange, intervalo e OpenTime) são atualizados,
+// então executar novamente no mesmo dia não duplica registros.
+func savePriceHistory(db *sql.DB, interval string, priceHistory []models.BinancePriceHistory) error {
+	if len(priceHistory) == 0 {
+		return nil
 	}
 
-	// Lê o corpo da resposta
-	body, err := io.ReadAll(resp.Body)
+	tx, err := db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("erro ao ler corpo da resposta: %w", err)
+		return err
 	}
-
-	// Decodifica o JSON em um array genérico
-	var rawKlines [][]any
-	if err := json.Unmarshal(body, &rawKlines); err != nil {
-		return nil, fmt.Errorf("erro ao decodificar JSON: %w", err)
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+        INSERT INTO price_history (
+            crypto_id, exchange_id, interval, open_time, close_time,
+            open, high, low, close, volume, quote_asset_volume,
+            number_of_trades, taker_buy_base_volume, taker_buy_quote_volume
+        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT (crypto_id, exchange_id, interval, open_time) DO UPDATE SET
+            close_time = excluded.close_time,
+            open = excluded.open,
+            high = excluded.high,
+            low = excluded.low,
+            close = excluded.close,
+            volume = excluded.volume,
+            quote_asset_volume = excluded.quote_asset_volume,
+            number_of_trades = excluded.number_of_trades,
+            taker_buy_base_volume = excluded.taker_buy_base_volume,
+            taker_buy_quote_volume = excluded.taker_buy_quote_volume;
+    `)
+	if err != nil {
+		return err
 	}
+	defer stmt.Close()
 
-	var klines []models.BinanceKline
-	for _, k := range rawKlines {
-		if len(k) < 12 {
-			continue
+	for _, record := range priceHistory {
+		_, err := stmt.Exec(
+			record.CryptoID,
+			record.ExchangeID,
+			interval,
+			record.OpenTime,
+			record.CloseTime,
+			record.OpenPrice,
+			record.HighPrice,
+			record.LowPrice,
+			record.ClosePrice,
+			record.Volume,
+			record.BaseAssetVolume,
+			record.NumberOfTrades,
+			record.TakerBuyBaseAssetVolume,
+			record.TakerBuyVolume,
+		)
+		if err != nil {
+			return err
 		}
-		openTime, _ := toInt64(k[0])
-		open, _ := toString(k[1])
-		high, _ := toString(k[2])
-		low, _ := toString(k[3])
-		closeVal, _ := toString(k[4])
-		volume, _ := toString(k[5])
-		closeTime, _ := toInt64(k[6])
-		quoteAssetVolume, _ := toString(k[7])
-		numberOfTrades, _ := toInt(k[8])
-		takerBuyBaseVolume, _ := toString(k[9])
-		takerBuyQuoteVolume, _ := toString(k[10])
-		// ignore k[11] (unused)
-
-		klines = append(klines, models.BinanceKline{
-			OpenTime:            openTime,
-			Open:                open,
-			High:                high,
-			Low:                 low,
-			Close:               closeVal,
-			Volume:              volume,
-			CloseTime:           closeTime,
-			QuoteAssetVolume:    quoteAssetVolume,
-			NumberOfTrades:      numberOfTrades,
-			TakerBuyBaseVolume:  takerBuyBaseVolume,
-			TakerBuyQuoteVolume: takerBuyQuoteVolume,
-		})
 	}
-
-	return klines, nil
+	return tx.Commit()
 }
 
-func savePriceHistoryToCSV(symbol string, priceHistory []models.BinancePriceHistory) error {
-	dir_path := "data/last_history/1m"
+// savePriceHistoryToCSV exporta os klines em DATA_DIR/last_history/<intervalo>/<SYMBOL>.csv
+func savePriceHistoryToCSV(symbol string, interval string, priceHistory []models.BinancePriceHistory) error {
+	dir_path := filepath.Join(os.Getenv("DATA_DIR"), "last_history", interval)
 
 	// Verifica se o diretório existe
 	if _, err := os.Stat(dir_path); os.IsNotExist(err) {
@@ -194,7 +230,7 @@ func savePriceHistoryToCSV(symbol string, priceHistory []models.BinancePriceHist
 	}
 
 	// Cria o arquivo CSV abrindo para escrita
-	file, err := os.Create(fmt.Sprintf("%s/%s.csv", dir_path, symbol))
+	file, err := os.Create(filepath.Join(dir_path, symbol+".csv"))
 	if err != nil {
 		return fmt.Errorf("erro ao criar arquivo CSV: %v", err)
 	}
@@ -204,23 +240,27 @@ func savePriceHistoryToCSV(symbol string, priceHistory []models.BinancePriceHist
 	writer := csv.NewWriter(file)
 	defer writer.Flush()
 
+	// Preços e volumes com a menor quantidade de dígitos que representa o valor exato
+	formatFloat := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
 	for _, record := range priceHistory {
 		err := writer.Write([]string{
 			record.Date.Format(time.RFC3339),
-			fmt.Sprintf("%.2f", record.Price),
+			formatFloat(record.Price),
 			fmt.Sprintf("%d", record.CryptoID),
 			fmt.Sprintf("%d", record.ExchangeID),
 			fmt.Sprintf("%d", record.OpenTime),
-			fmt.Sprintf("%.2f", record.OpenPrice),
-			fmt.Sprintf("%.2f", record.HighPrice),
-			fmt.Sprintf("%.2f", record.LowPrice),
-			fmt.Sprintf("%.2f", record.ClosePrice),
-			fmt.Sprintf("%.2f", record.Volume),
+			formatFloat(record.OpenPrice),
+			formatFloat(record.HighPrice),
+			formatFloat(record.LowPrice),
+			formatFloat(record.ClosePrice),
+			formatFloat(record.Volume),
 			fmt.Sprintf("%d", record.CloseTime),
-			fmt.Sprintf("%.2f", record.BaseAssetVolume),
+			formatFloat(record.BaseAssetVolume),
 			fmt.Sprintf("%d", record.NumberOfTrades),
-			fmt.Sprintf("%.2f", record.TakerBuyVolume),
-			fmt.Sprintf("%.2f", record.TakerBuyBaseAssetVolume),
+			formatFloat(record.TakerBuyVolume),
+			formatFloat(record.TakerBuyBaseAssetVolume),
 		})
 		if err != nil {
 			return err
@@ -231,9 +271,9 @@ func savePriceHistoryToCSV(symbol string, priceHistory []models.BinancePriceHist
 }
 
 // busca criptomoedas da binance habilitadas
-func fetchCryptos(db *sql.DB) ([]string, error) {
+func fetchCryptos(db *sql.DB) ([]enabledCrypto, error) {
 	query := `
-        SELECT c.symbol
+        SELECT c.id, e.id, c.symbol
         FROM cryptos c
         JOIN exchanges_cryptos ec ON c.id = ec.crypto_id
         JOIN exchanges e ON ec.exchange_id = e.id
@@ -246,49 +286,13 @@ func fetchCryptos(db *sql.DB) ([]string, error) {
 	}
 	defer rows.Close()
 
-	var symbols []string
+	var cryptos []enabledCrypto
 	for rows.Next() {
-		var symbol string
-		if err := rows.Scan(&symbol); err != nil {
+		var crypto enabledCrypto
+		if err := rows.Scan(&crypto.ID, &crypto.ExchangeID, &crypto.Symbol); err != nil {
 			return nil, err
 		}
-		symbols = append(symbols, symbol)
-	}
-	return symbols, nil
-}
-
-func toInt64(val interface{}) (int64, error) {
-	switch v := val.(type) {
-	case float64:
-		return int64(v), nil
-	case string:
-		return strconv.ParseInt(v, 10, 64)
-	default:
-		return 0, fmt.Errorf("cannot convert %T to int64", val)
-	}
-}
-
-func toString(val interface{}) (string, error) {
-	switch v := val.(type) {
-	case string:
-		return v, nil
-	case float64:
-		return fmt.Sprintf("%f", v), nil
-	case int:
-		return strconv.Itoa(v), nil
-	default:
-		return "", fmt.Errorf("cannot convert %T to string", val)
-	}
-}
-
-func toInt(val interface{}) (int, error) {
-	switch v := val.(type) {
-	case float64:
-		return int(v), nil
-	case string:
-		i, err := strconv.Atoi(v)
-		return i, err
-	default:
-		return 0, fmt.Errorf("cannot convert %T to int", val)
+		cryptos = append(cryptos, crypto)
 	}
+	return cryptos, rows.Err()
 }
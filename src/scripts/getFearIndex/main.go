@@ -0,0 +1,84 @@
+package getFearIndex
+
+import (
+	"app/src/database"
+	"app/src/sentiment"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+	_ "modernc.org/sqlite"
+)
+
+// Main importa as fontes de sentimento informadas (todas as registradas quando
+// names está vazio) até to (zero vai até agora). Com from zero cada fonte é
+// sincronizada a partir do último ponto gravado, ou desde o início do
+// histórico com all; caso contrário importa o intervalo [from, to].
+func Main(names []string, from, to time.Time, all bool) {
+	fmt.Println("=== Importador de índices de sentimento ===")
+
+	err := godotenv.Load()
+	if err != nil {
+		fmt.Println("Aviso: não foi possível carregar .env, usando variáveis do ambiente.")
+	}
+
+	if len(names) == 0 {
+		names = sentiment.Names()
+	}
+	var sources []sentiment.SentimentSource
+	for _, name := range names {
+		source, err := sentiment.Get(name)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		sources = append(sources, source)
+	}
+
+	// Conexão com o banco de dados
+	db, err := database.ConnectDatabase()
+	if err != nil {
+		log.Fatalf("Erro ao abrir o banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	_, err = database.Migrate(db)
+	if err != nil {
+		fmt.Printf("Erro ao garantir tabela: %v\n", err)
+		return
+	}
+
+	for _, source := range sources {
+		var result sentiment.ImportResult
+		switch {
+		case !from.IsZero() || all:
+			result, err = sentiment.Import(db, source, from, to)
+		default:
+			result, err = sentiment.Sync(db, source, to)
+		}
+		if err != nil {
+			fmt.Printf("Erro ao importar %s: %v\n", source.Name(), err)
+			continue
+		}
+
+		since := "início do histórico"
+		if !result.From.IsZero() {
+			since = result.From.Format("2006-01-02")
+		}
+		fmt.Printf("📈 %s (desde %s): %d registros inseridos, %d atualizados e %d sem alteração (%d páginas)\n",
+			source.Name(), since, result.Inserted, result.Updated, result.Skipped, result.Pages)
+	}
+}
+
+// GetFearAlternativeMe sincroniza a api.alternative.me a partir do último
+// ponto gravado, ou todo o histórico com isSearchForAllFlg
+func GetFearAlternativeMe(isSearchForAllFlg bool) {
+	Main([]string{"api.alternative.me"}, time.Time{}, time.Time{}, isSearchForAllFlg)
+}
+
+// GetFearCoinmarketcap sincroniza a CoinMarketCap a partir do último ponto
+// gravado, ou todo o histórico com isSearchForAllFlg
+func GetFearCoinmarketcap(isSearchForAllFlg bool) {
+	Main([]string{"CoinMarketCap"}, time.Time{}, time.Time{}, isSearchForAllFlg)
+}
@@ -0,0 +1,51 @@
+package importSentiment
+
+import (
+	"app/src/database"
+	"app/src/sentiment"
+	"fmt"
+	"log"
+
+	"github.com/joho/godotenv"
+	_ "modernc.org/sqlite"
+)
+
+// Main importa um arquivo local .csv ou .json de sentimento (por crypto ou de
+// mercado) para o fear_index com a fonte source
+func Main(path, source string) {
+	fmt.Println("=== Importador de sentimento de arquivo ===")
+
+	if path == "" {
+		fmt.Println("❌ Informe o arquivo com -file")
+		return
+	}
+	if source == "" {
+		source = sentiment.LocalSource
+	}
+
+	err := godotenv.Load()
+	if err != nil {
+		fmt.Println("Aviso: não foi possível carregar .env, usando variáveis do ambiente.")
+	}
+
+	// Conexão com o banco de dados
+	db, err := database.ConnectDatabase()
+	if err != nil {
+		log.Fatalf("Erro ao abrir o banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	_, err = database.Migrate(db)
+	if err != nil {
+		fmt.Printf("Erro ao garantir tabela: %v\n", err)
+		return
+	}
+
+	result, err := sentiment.ImportFile(db, source, path)
+	if err != nil {
+		fmt.Printf("❌ Erro ao importar %s: %v\n", path, err)
+		return
+	}
+	fmt.Printf("📈 %s → %s: %d registros inseridos, %d atualizados e %d sem alteração\n",
+		path, source, result.Inserted, result.Updated, result.Skipped)
+}
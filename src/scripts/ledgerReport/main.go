@@ -0,0 +1,100 @@
+package ledgerReport
+
+import (
+	"app/src/database"
+	"app/src/ledger"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	_ "github.com/joho/godotenv/autoload"
+	_ "modernc.org/sqlite"
+)
+
+// Pares e falhas listados no relatório
+const (
+	maxSymbols  = 20
+	maxFailures = 20
+)
+
+// Main exibe o estado do ledger de downloads do data.binance.vision: arquivos
+// baixados, 404 em cooldown, erros a tentar novamente e os pares com mais falhas
+func Main() {
+	db, err := database.ConnectDatabase()
+	if err != nil {
+		log.Fatalf("Erro ao abrir o banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := database.Migrate(db); err != nil {
+		fmt.Printf("❌ Erro ao aplicar migrações: %v\n", err)
+		return
+	}
+
+	config := ledger.DefaultConfig()
+	downloads, err := ledger.Open(db, config, os.Getenv("DATA_DIR"))
+	if err != nil {
+		fmt.Printf("❌ Erro ao abrir o ledger de downloads: %v\n", err)
+		return
+	}
+
+	summary, err := downloads.Summary()
+	if err != nil {
+		fmt.Printf("❌ Erro ao ler o ledger de downloads: %v\n", err)
+		return
+	}
+
+	fmt.Println("\n📒 LEDGER DE DOWNLOADS")
+	fmt.Println(strings.Repeat("=", 40))
+	fmt.Printf("✅ Disponíveis (200): %d (%d com SHA-256 verificado)\n", summary.Downloaded, summary.Verified)
+	fmt.Printf("🚫 404 em cooldown: %d (nova tentativa após %s, até %s)\n", summary.NotFoundWaiting, config.NotFoundCooldown, config.MaxNotFoundCooldown)
+	fmt.Printf("🔁 404 prontos para nova tentativa: %d\n", summary.NotFoundReady)
+	fmt.Printf("⚠️ Erros de rede (tentados na próxima execução): %d\n", summary.NetworkErrors)
+
+	statuses := make([]int, 0, len(summary.OtherFailures))
+	for status := range summary.OtherFailures {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		label := fmt.Sprintf("status %d", status)
+		if status == 200 {
+			label = "checksum divergente ou erro na extração"
+		}
+		fmt.Printf("❗ Falhas (%s): %d\n", label, summary.OtherFailures[status])
+	}
+
+	symbols, err := downloads.Symbols(maxSymbols)
+	if err != nil {
+		fmt.Printf("❌ Erro ao ler o ledger de downloads: %v\n", err)
+		return
+	}
+	if len(symbols) > 0 {
+		fmt.Println()
+		fmt.Printf("%-14s %10s %8s %8s\n", "Par", "Baixados", "404", "Falhas")
+		for _, s := range symbols {
+			fmt.Printf("%-14s %10d %8d %8d\n", s.Symbol, s.Downloaded, s.NotFound, s.Failed)
+		}
+	}
+
+	failures, err := downloads.Failures(maxFailures)
+	if err != nil {
+		fmt.Printf("❌ Erro ao ler o ledger de downloads: %v\n", err)
+		return
+	}
+	if len(failures) > 0 {
+		fmt.Println("\nÚltimas falhas:")
+		for _, entry := range failures {
+			status := "erro de rede"
+			if entry.HTTPStatus != 0 {
+				status = fmt.Sprintf("status %d", entry.HTTPStatus)
+			}
+			fmt.Printf("  %s %s %s (%s, %d tentativas, última em %s): %s\n",
+				entry.Symbol, entry.Date, entry.URL, status, entry.Attempts,
+				entry.LastAttempt.UTC().Format("2006-01-02 15:04:05"), entry.Error)
+		}
+	}
+	fmt.Println(strings.Repeat("=", 40))
+}
@@ -0,0 +1,234 @@
+package repairGaps
+
+import (
+	"app/src/binanceapi"
+	"app/src/models"
+	"app/src/utils"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dayGaps são os candles fechados ausentes no CSV de um dia
+type dayGaps struct {
+	Day    time.Time
+	Path   string
+	Exists bool
+	// O arquivo usa timestamps em microssegundos (data.binance.vision desde 2025)
+	Micro bool
+	// OpenTime (ms) dos candles ausentes, em ordem
+	Missing []int64
+}
+
+// Sequência de candles ausentes consecutivos [From, To]
+type gapRange struct {
+	From int64
+	To   int64
+}
+
+func (r gapRange) String(step int64) string {
+	from := time.UnixMilli(r.From).UTC().Format("15:04")
+	to := time.UnixMilli(r.To).UTC().Format("15:04")
+	count := (r.To-r.From)/step + 1
+	if count == 1 {
+		return from
+	}
+	return fmt.Sprintf("%s–%s (%d)", from, to, count)
+}
+
+// ranges agrupa os candles ausentes em sequências consecutivas
+func (g dayGaps) ranges(step int64) []gapRange {
+	var ranges []gapRange
+	for _, openTime := range g.Missing {
+		if n := len(ranges); n > 0 && ranges[n-1].To+step == openTime {
+			ranges[n-1].To = openTime
+			continue
+		}
+		ranges = append(ranges, gapRange{From: openTime, To: openTime})
+	}
+	return ranges
+}
+
+// scanDay compara o CSV do dia com os candles esperados até closedUntil (o
+// OpenTime do último candle fechado)
+func scanDay(path string, day time.Time, step, closedUntil int64) (dayGaps, error) {
+	gaps := dayGaps{Day: day, Path: path}
+	dayStart := day.UnixMilli()
+	dayEnd := day.AddDate(0, 0, 1).UnixMilli()
+
+	present := make(map[int64]bool)
+	file, err := os.Open(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return gaps, err
+	default:
+		defer file.Close()
+		gaps.Exists = true
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			openTime, ok := parseOpenTime(scanner.Text())
+			if !ok {
+				continue
+			}
+			if openTime > 1e14 {
+				gaps.Micro = true
+			}
+			present[utils.NormalizeTimestampMs(openTime)] = true
+		}
+		if err := scanner.Err(); err != nil {
+			return gaps, err
+		}
+	}
+
+	for t := dayStart; t < dayEnd && t <= closedUntil; t += step {
+		if !present[t] {
+			gaps.Missing = append(gaps.Missing, t)
+		}
+	}
+	return gaps, nil
+}
+
+// parseOpenTime lê a primeira coluna de uma linha do CSV (ignora o cabeçalho)
+func parseOpenTime(line string) (int64, bool) {
+	field, _, _ := strings.Cut(line, ",")
+	openTime, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64)
+	return openTime, err == nil
+}
+
+// fillDay busca na API os candles ausentes do dia e os grava no CSV. Retorna
+// quantos foram recuperados; os demais não existem na Binance (ex: manutenção).
+func fillDay(client *binanceapi.Client, pair, interval string, step int64, gaps dayGaps) (int, error) {
+	missing := make(map[int64]bool, len(gaps.Missing))
+	for _, openTime := range gaps.Missing {
+		missing[openTime] = true
+	}
+
+	recovered := make(map[int64][]string)
+	for _, r := range gaps.ranges(step) {
+		klines, err := client.KlinesRange(context.Background(), pair, interval, r.From, r.To)
+		if err != nil {
+			return 0, err
+		}
+		for _, kline := range klines {
+			if missing[kline.OpenTime] {
+				recovered[kline.OpenTime] = klineRecord(kline)
+			}
+		}
+	}
+	if len(recovered) == 0 {
+		return 0, nil
+	}
+	return len(recovered), mergeKlines(gaps, recovered)
+}
+
+// klineRecord monta a linha do kline nas colunas dos CSVs do data.binance.vision
+func klineRecord(kline models.BinanceKline) []string {
+	ignore := kline.Ignore
+	if ignore == "" {
+		ignore = "0"
+	}
+	return []string{
+		strconv.FormatInt(kline.OpenTime, 10),
+		kline.Open,
+		kline.High,
+		kline.Low,
+		kline.Close,
+		kline.Volume,
+		strconv.FormatInt(kline.CloseTime, 10),
+		kline.QuoteAssetVolume,
+		strconv.Itoa(kline.NumberOfTrades),
+		kline.TakerBuyBaseVolume,
+		kline.TakerBuyQuoteVolume,
+		ignore,
+	}
+}
+
+// mergeKlines regrava o CSV do dia com os klines recuperados, em ordem de
+// OpenTime e na mesma unidade de timestamp das linhas existentes
+func mergeKlines(gaps dayGaps, recovered map[int64][]string) error {
+	lines := make(map[int64]string)
+	var header string
+	if gaps.Exists {
+		file, err := os.Open(gaps.Path)
+		if err != nil {
+			return err
+		}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := scanner.Text()
+			openTime, ok := parseOpenTime(line)
+			if !ok {
+				if header == "" && strings.TrimSpace(line) != "" {
+					header = line
+				}
+				continue
+			}
+			lines[utils.NormalizeTimestampMs(openTime)] = line
+		}
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+
+	for openTime, kline := range recovered {
+		if gaps.Micro {
+			kline = toMicroseconds(kline)
+		}
+		lines[openTime] = strings.Join(kline, ",")
+	}
+
+	openTimes := make([]int64, 0, len(lines))
+	for openTime := range lines {
+		openTimes = append(openTimes, openTime)
+	}
+	sort.Slice(openTimes, func(i, j int) bool { return openTimes[i] < openTimes[j] })
+
+	if err := os.MkdirAll(filepath.Dir(gaps.Path), 0755); err != nil {
+		return err
+	}
+	// Grava em um arquivo temporário para não deixar o CSV pela metade
+	tmpPath := gaps.Path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	if header != "" {
+		writer.WriteString(header + "\n")
+	}
+	for _, openTime := range openTimes {
+		writer.WriteString(lines[openTime] + "\n")
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, gaps.Path)
+}
+
+// toMicroseconds converte o OpenTime e o CloseTime de um kline da API (ms)
+// para o formato em microssegundos dos arquivos do data.binance.vision
+func toMicroseconds(kline []string) []string {
+	converted := append([]string(nil), kline...)
+	if openTime, err := strconv.ParseInt(kline[0], 10, 64); err == nil {
+		converted[0] = strconv.FormatInt(openTime*1000, 10)
+	}
+	if closeTime, err := strconv.ParseInt(kline[6], 10, 64); err == nil {
+		converted[6] = strconv.FormatInt(closeTime*1000+999, 10)
+	}
+	return converted
+}
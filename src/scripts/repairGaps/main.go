@@ -0,0 +1,184 @@
+package repairGaps
+
+import (
+	"app/src/binanceapi"
+	"app/src/database"
+	"app/src/utils"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "github.com/joho/godotenv/autoload"
+	_ "modernc.org/sqlite"
+)
+
+// Config do RepairGaps
+type Config struct {
+	// Dias verificados (inclusivos). Dias futuros são ignorados e o dia corrente
+	// só é verificado até o último candle fechado.
+	Start time.Time
+	End   time.Time
+	// Intervalo dos klines
+	Interval string
+	// URL base da API REST usada para preencher as lacunas
+	BaseURL string
+}
+
+// DefaultConfig repara klines de 1 minuto usando a API da Binance, que pode
+// apontar para um servidor local via BINANCE_API_BASE_URL
+func DefaultConfig() Config {
+	return Config{
+		Interval: "1m",
+		BaseURL:  binanceapi.DefaultConfig().BaseURL,
+	}
+}
+
+// Main verifica os CSVs diários de klines das criptomoedas habilitadas em
+// DATA_DIR, lista os dias e candles ausentes e os preenche com a API REST da
+// Binance. Os candles recuperados são gravados nos mesmos CSVs lidos pelo
+// GenerateDataset e pelo Backtest.
+func Main(config Config) {
+	log.SetFlags(log.LstdFlags | log.Lmsgprefix)
+	log.SetPrefix("INFO: ")
+
+	duration, err := utils.IntervalDuration(config.Interval)
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return
+	}
+	step := duration.Milliseconds()
+
+	db, err := database.ConnectDatabase()
+	if err != nil {
+		log.Fatalf("Erro ao abrir o banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	pairs, err := fetchPairs(db)
+	if err != nil {
+		log.Printf("❌ Erro ao buscar criptomoedas: %v", err)
+		return
+	}
+	if len(pairs) == 0 {
+		log.Println("⚠️ Nenhuma criptomoeda habilitada encontrada")
+		return
+	}
+
+	klineBasePath := filepath.Join(os.Getenv("DATA_DIR"), "data.binance.vision", "data", "spot", "daily", "klines")
+	apiConfig := binanceapi.DefaultConfig()
+	apiConfig.BaseURL = config.BaseURL
+	client := binanceapi.New(apiConfig)
+
+	// Apenas candles já fechados podem ser recuperados
+	now := time.Now().UTC()
+	closedUntil := now.UnixMilli()/step*step - step
+	end := config.End
+	if today := now.Truncate(24 * time.Hour); end.After(today) {
+		end = today
+	}
+	log.Printf("🔎 Verificando %d pares de %s até %s (%s)", len(pairs), config.Start.Format("2006-01-02"), end.Format("2006-01-02"), config.Interval)
+
+	var totalMissing, totalRecovered, totalUnavailable int
+	for _, pair := range pairs {
+		var days []dayGaps
+		for day := config.Start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			path := klineFilePath(klineBasePath, pair, config.Interval, day)
+			gaps, err := scanDay(path, day, step, closedUntil)
+			if err != nil {
+				log.Printf("⚠️ Erro ao ler %s: %v", path, err)
+				continue
+			}
+			if len(gaps.Missing) > 0 {
+				days = append(days, gaps)
+			}
+		}
+		if len(days) == 0 {
+			log.Printf("✅ %s: nenhuma lacuna", pair)
+			continue
+		}
+		reportGaps(pair, days, step)
+
+		for _, gaps := range days {
+			totalMissing += len(gaps.Missing)
+			recovered, err := fillDay(client, pair, config.Interval, step, gaps)
+			if err != nil {
+				log.Printf("❌ %s %s: %v", pair, gaps.Day.Format("2006-01-02"), err)
+				continue
+			}
+			totalRecovered += recovered
+			totalUnavailable += len(gaps.Missing) - recovered
+		}
+	}
+
+	log.Printf("🩹 %d candles ausentes, %d recuperados via REST e %d sem dados na Binance", totalMissing, totalRecovered, totalUnavailable)
+}
+
+// reportGaps lista os dias sem arquivo e os intervalos de candles ausentes
+func reportGaps(pair string, days []dayGaps, step int64) {
+	missing := 0
+	absent := 0
+	for _, gaps := range days {
+		missing += len(gaps.Missing)
+		if !gaps.Exists {
+			absent++
+		}
+	}
+	log.Printf("📉 %s: %d candles ausentes em %d dias (%d dias sem arquivo)", pair, missing, len(days), absent)
+
+	for _, gaps := range days {
+		date := gaps.Day.Format("2006-01-02")
+		if !gaps.Exists {
+			log.Printf("   %s: arquivo ausente (%d candles)", date, len(gaps.Missing))
+			continue
+		}
+		ranges := gaps.ranges(step)
+		parts := make([]string, 0, len(ranges))
+		for i, r := range ranges {
+			if i == maxReportedRanges {
+				parts = append(parts, fmt.Sprintf("... +%d", len(ranges)-i))
+				break
+			}
+			parts = append(parts, r.String(step))
+		}
+		log.Printf("   %s: %d candles em %s", date, len(gaps.Missing), strings.Join(parts, ", "))
+	}
+}
+
+// Intervalos listados por dia no relatório
+const maxReportedRanges = 10
+
+// fetchPairs retorna os pares USDT das criptomoedas habilitadas na Binance
+func fetchPairs(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`
+        SELECT DISTINCT c.symbol
+        FROM cryptos c
+        JOIN exchanges_cryptos ec ON c.id = ec.crypto_id
+        JOIN exchanges e ON ec.exchange_id = e.id
+        WHERE LOWER(e.name) LIKE '%binance%'
+        AND c.is_enabled = 1
+        ORDER BY c.symbol;
+    `)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pairs []string
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, symbol+"USDT")
+	}
+	return pairs, rows.Err()
+}
+
+// klineFilePath monta o caminho do CSV diário de klines de um par
+func klineFilePath(klineBasePath, cryptoPair, interval string, day time.Time) string {
+	return filepath.Join(klineBasePath, cryptoPair, interval, "csv", cryptoPair+"-"+interval+"-"+day.Format("2006-01-02")+".csv")
+}
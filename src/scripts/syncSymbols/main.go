@@ -0,0 +1,224 @@
+package syncSymbols
+
+import (
+	"app/src/binanceapi"
+	"app/src/constants"
+	"app/src/database"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	_ "github.com/joho/godotenv/autoload"
+	_ "modernc.org/sqlite"
+)
+
+type exchangeInfoResponse struct {
+	Symbols []exchangeSymbol `json:"symbols"`
+}
+
+type exchangeSymbol struct {
+	Symbol               string   `json:"symbol"`
+	Status               string   `json:"status"`
+	BaseAsset            string   `json:"baseAsset"`
+	QuoteAsset           string   `json:"quoteAsset"`
+	IsSpotTradingAllowed bool     `json:"isSpotTradingAllowed"`
+	Permissions          []string `json:"permissions"`
+}
+
+// Main sincroniza as tabelas cryptos/exchanges_cryptos com os pares USDT spot
+// listados no exchangeInfo da Binance. Pares fora de negociação são desativados.
+func Main() {
+	log.SetFlags(log.LstdFlags | log.Lmsgprefix)
+	log.SetPrefix("INFO: ")
+
+	db, err := database.ConnectDatabase()
+	if err != nil {
+		log.Fatalf("Erro ao abrir o banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := database.Migrate(db); err != nil {
+		log.Printf("❌ Erro ao aplicar migrações: %v", err)
+		return
+	}
+
+	symbols, err := fetchExchangeInfo()
+	if err != nil {
+		log.Printf("❌ Erro ao buscar exchangeInfo: %v", err)
+		return
+	}
+
+	// Status por ativo base dos pares USDT spot
+	assets := make(map[string]string)
+	for _, s := range symbols {
+		if s.QuoteAsset != "USDT" || !isSpot(s) {
+			continue
+		}
+		assets[s.BaseAsset] = s.Status
+	}
+	log.Printf("📊 %d pares USDT spot encontrados na Binance", len(assets))
+
+	inserted, updated, disabled, err := upsertCryptos(db, assets)
+	if err != nil {
+		log.Printf("❌ Erro ao sincronizar criptomoedas: %v", err)
+		return
+	}
+
+	log.Printf("✨ Sincronização concluída: %d inseridas, %d atualizadas, %d desativadas", inserted, updated, disabled)
+}
+
+func isSpot(s exchangeSymbol) bool {
+	if s.IsSpotTradingAllowed {
+		return true
+	}
+	for _, permission := range s.Permissions {
+		if permission == "SPOT" {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchExchangeInfo busca os pares da Binance. A URL base pode apontar para um
+// servidor local via BINANCE_API_BASE_URL.
+func fetchExchangeInfo() ([]exchangeSymbol, error) {
+	client := binanceapi.New(binanceapi.DefaultConfig())
+	body, err := client.Get(context.Background(), constants.BINANCE_EXCHANGE_INFO_PATH, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro HTTP: %v", err)
+	}
+
+	var info exchangeInfoResponse
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar JSON: %v", err)
+	}
+
+	return info.Symbols, nil
+}
+
+// upsertCryptos grava status e vínculo com a Binance de cada ativo. Ativos
+// que não estão em TRADING, ou que sumiram do exchangeInfo, são desativados;
+// os que voltam a TRADING são reativados. Ativos já em TRADING (ou ainda sem
+// status) mantêm o is_enabled atual, que pode ter sido definido pelo DisableCryptos.
+// Retorna quantos ativos foram inseridos, atualizados e desativados; só contam
+// como desativados os que estavam ativos antes desta sincronização.
+func upsertCryptos(db *sql.DB, assets map[string]string) (int, int, int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT OR IGNORE INTO exchanges (name) VALUES ('Binance')`); err != nil {
+		return 0, 0, 0, fmt.Errorf("erro ao garantir exchange Binance: %w", err)
+	}
+
+	var exchangeID int
+	err = tx.QueryRow(`SELECT id FROM exchanges WHERE LOWER(name) LIKE '%binance%' ORDER BY id LIMIT 1`).Scan(&exchangeID)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("erro ao buscar exchange Binance: %w", err)
+	}
+
+	existing, err := querySymbols(tx, `SELECT symbol FROM cryptos`)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	enabled, err := querySymbols(tx, `SELECT symbol FROM cryptos WHERE is_enabled = 1`)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	linked, err := querySymbols(tx, `
+		SELECT c.symbol
+		FROM cryptos c
+		JOIN exchanges_cryptos ec ON c.id = ec.crypto_id
+		WHERE ec.exchange_id = ?`, exchangeID)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	inserted, updated, disabled := 0, 0, 0
+	for asset, status := range assets {
+		isEnabled := 1
+		if status != "TRADING" {
+			isEnabled = 0
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO cryptos (symbol, status, is_enabled) VALUES (?, ?, ?)
+			ON CONFLICT(symbol) DO UPDATE SET
+				status = excluded.status,
+				is_enabled = CASE
+					WHEN excluded.status <> 'TRADING' THEN 0
+					WHEN cryptos.status IS NOT NULL AND cryptos.status <> 'TRADING' THEN 1
+					ELSE cryptos.is_enabled
+				END`,
+			asset, status, isEnabled,
+		)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("erro ao gravar %s: %w", asset, err)
+		}
+
+		_, err = tx.Exec(`
+			INSERT INTO exchanges_cryptos (exchange_id, crypto_id)
+			SELECT ?, c.id FROM cryptos c
+			WHERE c.symbol = ?
+			AND NOT EXISTS (
+				SELECT 1 FROM exchanges_cryptos ec WHERE ec.exchange_id = ? AND ec.crypto_id = c.id
+			)`,
+			exchangeID, asset, exchangeID,
+		)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("erro ao vincular %s à Binance: %w", asset, err)
+		}
+
+		if existing[asset] {
+			updated++
+		} else {
+			inserted++
+		}
+		if isEnabled == 0 && enabled[asset] {
+			disabled++
+		}
+	}
+
+	// Ativos vinculados à Binance que não aparecem mais como par USDT
+	for symbol := range linked {
+		if _, ok := assets[symbol]; ok {
+			continue
+		}
+		_, err := tx.Exec(`UPDATE cryptos SET status = 'DELISTED', is_enabled = 0 WHERE symbol = ?`, symbol)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("erro ao desativar %s: %w", symbol, err)
+		}
+		if enabled[symbol] {
+			log.Printf("🚫 %s não está mais listada na Binance. Desativando...", symbol)
+			disabled++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, 0, err
+	}
+	return inserted, updated, disabled, nil
+}
+
+// Conjunto de símbolos retornados pela consulta
+func querySymbols(tx *sql.Tx, query string, args ...any) (map[string]bool, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar criptos: %w", err)
+	}
+	defer rows.Close()
+
+	symbols := make(map[string]bool)
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			return nil, fmt.Errorf("erro ao ler linha: %w", err)
+		}
+		symbols[symbol] = true
+	}
+	return symbols, rows.Err()
+}
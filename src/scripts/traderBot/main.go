@@ -1,74 +1,129 @@
 package traderBot
 
 import (
+	"app/src/database"
+	"app/src/exchange"
+	"app/src/models"
+	"app/src/strategy"
+	"app/src/stream"
 	"context"
 	"fmt"
 	"log"
 	"os"
-	"strconv"
-	"time"
+	"os/signal"
 
 	"github.com/adshao/go-binance/v2"
+	_ "modernc.org/sqlite"
 )
 
-func Main() {
+// Config define o que o bot executa
+type Config struct {
+	Strategy       string
+	StrategyParams strategy.Params
+	Symbols        []string
+	Interval       string
+	Paper          bool
+	PaperBalance   float64
+}
+
+// DefaultConfig reproduz o comportamento original do bot
+func DefaultConfig() Config {
+	return Config{
+		Strategy:     "percentChange",
+		Symbols:      []string{"BTCUSDT"},
+		Interval:     "1m",
+		PaperBalance: 1000,
+	}
+}
+
+// Main executa o bot. Com config.Paper=true as ordens são enviadas para uma
+// exchange simulada com PaperBalance de saldo inicial no ativo de cotação dos
+// pares (todos precisam ter o mesmo), sem tocar na conta real.
+func Main(config Config) {
 	apiKey := os.Getenv("BINANCE_API_KEY")
 	secretKey := os.Getenv("BINANCE_API_SECRET")
 
 	client := binance.NewClient(apiKey, secretKey)
 
-	symbol := "BTCUSDT"
-	quantity := 0.001
+	strat, err := strategy.New(config.Strategy, config.StrategyParams)
+	if err != nil {
+		log.Fatalf("Erro ao criar estratégia: %v", err)
+	}
+	fmt.Printf("🧠 Estratégia: %s | Símbolos: %v\n", strat.Name(), config.Symbols)
+
+	var ex exchange.Exchange
+	if config.Paper {
+		db, err := database.ConnectDatabase()
+		if err != nil {
+			log.Fatalf("Erro ao abrir o banco de dados: %v", err)
+		}
+		defer db.Close()
 
-	for {
-		err := tradeLogic(client, symbol, quantity)
+		quoteAsset, err := exchange.QuoteAsset(config.Symbols)
 		if err != nil {
-			log.Println("Erro na estratégia:", err)
+			log.Fatalf("Erro ao iniciar paper trading: %v", err)
 		}
-		time.Sleep(1 * time.Minute)
+		paperExchange, err := exchange.NewPaper(db, quoteAsset, config.PaperBalance)
+		if err != nil {
+			log.Fatalf("Erro ao iniciar paper trading: %v", err)
+		}
+		fmt.Printf("🧪 Modo paper trading ativo com saldo inicial de %.2f %s\n", config.PaperBalance, quoteAsset)
+		ex = paperExchange
+	} else {
+		ex = exchange.NewBinance(client)
 	}
-}
 
-func tradeLogic(client *binance.Client, symbol string, quantity float64) error {
-	klines, err := client.NewKlinesService().
-		Symbol(symbol).
-		Interval("1m").
-		Limit(2).
-		Do(context.Background())
+	// Candles fechados chegam pelo WebSocket da Binance, com reconexão e
+	// backfill via REST dos candles perdidos durante uma queda
+	streamConfig := stream.DefaultConfig()
+	streamConfig.Symbols = config.Symbols
+	streamConfig.Interval = config.Interval
+	klines, err := stream.New(streamConfig)
 	if err != nil {
-		return fmt.Errorf("erro ao obter Klines: %v", err)
+		log.Fatalf("Erro ao criar o stream de klines: %v", err)
 	}
+	candles, unsubscribe := klines.Subscribe(len(config.Symbols))
+	defer unsubscribe()
 
-	prevClose, _ := strconv.ParseFloat(klines[0].Close, 64)
-	lastClose, _ := strconv.ParseFloat(klines[1].Close, 64)
+	// Ctrl+C encerra o stream e o bot
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go klines.Run(ctx)
 
-	change := (lastClose - prevClose) / prevClose * 100
-	fmt.Printf("Preço anterior: %.2f, atual: %.2f, variação: %.2f%%\n", prevClose, lastClose, change)
+	for candle := range candles {
+		if err := tradeLogic(ex, strat, candle); err != nil {
+			log.Println("Erro na estratégia:", err)
+		}
+	}
+	fmt.Println("👋 TraderBot encerrado")
+}
 
-	if change <= -0.5 {
-		fmt.Println("🔽 Queda detectada. Comprando...")
-		return executeOrder(client, symbol, quantity, binance.SideTypeBuy)
-	} else if change >= 0.5 {
-		fmt.Println("🔼 Alta detectada. Vendendo...")
-		return executeOrder(client, symbol, quantity, binance.SideTypeSell)
-	} else {
+func tradeLogic(ex exchange.Exchange, strat strategy.Strategy, candle models.Candle) error {
+	symbol := candle.Symbol
+	signal := strat.OnCandle(candle)
+	if signal.Reason != "" {
+		fmt.Printf("%s: %s\n", symbol, signal.Reason)
+	}
+
+	switch signal.Action {
+	case strategy.ActionBuy:
+		fmt.Println("🔽 Sinal de compra. Comprando...")
+		if err := executeOrder(ex, symbol, signal.Quantity, exchange.SideBuy, candle.Close); err != nil {
+			return err
+		}
+	case strategy.ActionSell:
+		fmt.Println("🔼 Sinal de venda. Vendendo...")
+		if err := executeOrder(ex, symbol, signal.Quantity, exchange.SideSell, candle.Close); err != nil {
+			return err
+		}
+	default:
 		fmt.Println("⏸ Sem ação no momento.")
 	}
 
 	return nil
 }
 
-func executeOrder(client *binance.Client, symbol string, quantity float64, side binance.SideType) error {
-	order, err := client.NewCreateOrderService().
-		Symbol(symbol).
-		Side(side).
-		Type(binance.OrderTypeMarket).
-		Quantity(fmt.Sprintf("%f", quantity)).
-		Do(context.Background())
-	if err != nil {
-		return fmt.Errorf("erro ao executar ordem: %v", err)
-	}
-
-	fmt.Println("✅ Ordem executada:", order)
-	return nil
+func executeOrder(ex exchange.Exchange, symbol string, quantity float64, side exchange.Side, lastPrice float64) error {
+	_, err := ex.ExecuteOrder(symbol, side, quantity, lastPrice)
+	return err
 }
@@ -0,0 +1,65 @@
+package sentiment
+
+import (
+	"app/src/constants"
+	"app/src/dto"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+func init() {
+	Register(alternativeMe{})
+}
+
+// alternativeMe é o Fear & Greed Index da api.alternative.me. A API retorna os
+// últimos limit dias (limit=0 retorna todo o histórico) em uma única página.
+type alternativeMe struct{}
+
+func (alternativeMe) Name() string   { return "api.alternative.me" }
+func (alternativeMe) Column() string { return "fear_api_alternative_me" }
+
+func (alternativeMe) Fetch(from, to time.Time, page int) ([]byte, bool, error) {
+	limit := 0
+	if !from.IsZero() {
+		limit = int(time.Since(from).Hours()/24) + 2
+	}
+
+	resp, err := httpClient.Get(fmt.Sprintf("%s/?limit=%d", constants.ALTERNATIVE_ME_API, limit))
+	if err != nil {
+		return nil, false, fmt.Errorf("erro HTTP: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, false, fmt.Errorf("resposta inválida: %s", string(body))
+	}
+	return body, false, nil
+}
+
+func (alternativeMe) Parse(body []byte) ([]Point, error) {
+	var apiResp dto.AlternativeAPIResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar JSON: %v", err)
+	}
+
+	points := make([]Point, 0, len(apiResp.Data))
+	for _, item := range apiResp.Data {
+		timestamp, err := strconv.ParseInt(item.Timestamp, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("timestamp inválido %q: %v", item.Timestamp, err)
+		}
+		value, err := strconv.ParseFloat(item.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("valor inválido %q: %v", item.Value, err)
+		}
+		points = append(points, Point{Date: time.Unix(timestamp, 0).UTC(), Value: value})
+	}
+	return points, nil
+}
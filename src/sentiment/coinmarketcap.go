@@ -0,0 +1,87 @@
+package sentiment
+
+import (
+	"app/src/constants"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+func init() {
+	Register(coinMarketCap{})
+}
+
+// Pontos por página da API histórica da CoinMarketCap
+const coinMarketCapPageSize = 50
+
+// coinMarketCap é o Fear & Greed Index da CoinMarketCap. O histórico vem do
+// mais recente para o mais antigo, em páginas de coinMarketCapPageSize dias.
+// Necessita de COINMARKETCAP_API_KEY.
+type coinMarketCap struct{}
+
+type coinMarketCapResponse struct {
+	Data []coinMarketCapData `json:"data"`
+}
+
+type coinMarketCapData struct {
+	Timestamp string  `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+func (coinMarketCap) Name() string   { return "CoinMarketCap" }
+func (coinMarketCap) Column() string { return "fear_coinmarketcap" }
+
+func (coinMarketCap) Fetch(from, to time.Time, page int) ([]byte, bool, error) {
+	apiKey := os.Getenv("COINMARKETCAP_API_KEY")
+	if apiKey == "" {
+		return nil, false, fmt.Errorf("variável COINMARKETCAP_API_KEY não definida")
+	}
+
+	req, err := http.NewRequest("GET", constants.COINMARKETCAP_FEAR_HISTORICAL_API, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("X-CMC_PRO_API_KEY", apiKey)
+
+	q := req.URL.Query()
+	q.Add("limit", strconv.Itoa(coinMarketCapPageSize))
+	q.Add("start", strconv.Itoa(page*coinMarketCapPageSize+1))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("erro HTTP: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, false, fmt.Errorf("resposta inválida: %s", string(body))
+	}
+	// O importador para quando a página vem vazia ou passa de from
+	return body, true, nil
+}
+
+func (coinMarketCap) Parse(body []byte) ([]Point, error) {
+	var apiResp coinMarketCapResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar JSON: %v", err)
+	}
+
+	points := make([]Point, 0, len(apiResp.Data))
+	for _, item := range apiResp.Data {
+		timestamp, err := strconv.ParseInt(item.Timestamp, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("timestamp inválido %q: %v", item.Timestamp, err)
+		}
+		points = append(points, Point{Date: time.Unix(timestamp, 0).UTC(), Value: item.Value})
+	}
+	return points, nil
+}
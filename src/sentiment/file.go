@@ -0,0 +1,152 @@
+package sentiment
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Linha de um arquivo JSON de sentimento. Date aceita os mesmos formatos do CSV.
+type filePoint struct {
+	Date   json.RawMessage `json:"date"`
+	Symbol string          `json:"symbol"`
+	Value  *float64        `json:"value"`
+}
+
+// ImportFile grava no fear_index, com a fonte source, os pontos de um arquivo
+// local .csv ou .json produzido fora da aplicação (ex: scores por crypto dos
+// analistas). Cada ponto tem date, symbol e value: symbol é a crypto (ex:
+// "BTC") e vazio indica um valor de mercado.
+//
+// O CSV precisa de cabeçalho com as colunas date, symbol e value (em qualquer
+// ordem). O JSON é uma lista de objetos com os mesmos campos. Datas aceitas:
+// YYYY-MM-DD, "YYYY-MM-DD HH:MM:SS" ou RFC3339 (UTC quando sem fuso) e
+// timestamps Unix em segundos ou milissegundos.
+func ImportFile(db *sql.DB, source, path string) (ImportResult, error) {
+	result := ImportResult{Source: source}
+
+	var points []Point
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		points, err = readCSVPoints(path)
+	case ".json":
+		points, err = readJSONPoints(path)
+	default:
+		return result, fmt.Errorf("formato de arquivo não suportado: %s (use .csv ou .json)", path)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	result.Pages = 1
+	result.Fetched = len(points)
+	if len(points) == 0 {
+		return result, nil
+	}
+	return result, savePoints(db, source, points, &result)
+}
+
+func readCSVPoints(path string) ([]Point, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler o cabeçalho de %s: %v", path, err)
+	}
+	columns := map[string]int{"date": -1, "symbol": -1, "value": -1}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; ok {
+			columns[name] = i
+		}
+	}
+	for name, i := range columns {
+		if i < 0 {
+			return nil, fmt.Errorf("coluna %s não encontrada em %s", name, path)
+		}
+	}
+
+	var points []Point
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s linha %d: %v", path, line, err)
+		}
+		date, err := parsePointDate(record[columns["date"]])
+		if err != nil {
+			return nil, fmt.Errorf("%s linha %d: %v", path, line, err)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[columns["value"]]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s linha %d: valor inválido %q", path, line, record[columns["value"]])
+		}
+		points = append(points, Point{Target: normalizeTarget(record[columns["symbol"]]), Date: date, Value: value})
+	}
+	return points, nil
+}
+
+func readJSONPoints(path string) ([]Point, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var items []filePoint
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar %s: %v", path, err)
+	}
+
+	points := make([]Point, 0, len(items))
+	for i, item := range items {
+		// A data pode vir como texto ou como timestamp numérico
+		raw := strings.Trim(string(item.Date), `"`)
+		date, err := parsePointDate(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s item %d: %v", path, i, err)
+		}
+		if item.Value == nil {
+			return nil, fmt.Errorf("%s item %d: campo value ausente", path, i)
+		}
+		points = append(points, Point{Target: normalizeTarget(item.Symbol), Date: date, Value: *item.Value})
+	}
+	return points, nil
+}
+
+// parsePointDate converte a data de um ponto importado de arquivo para UTC
+func parsePointDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"2006-01-02", dateLayout, time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+		// Timestamps com 13 dígitos ou mais estão em milissegundos
+		if timestamp >= 1e12 {
+			return time.UnixMilli(timestamp).UTC(), nil
+		}
+		return time.Unix(timestamp, 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("data inválida %q", value)
+}
+
+// normalizeTarget deixa o símbolo no formato das colunas do dataset (ex: "BTC")
+func normalizeTarget(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
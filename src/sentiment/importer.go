@@ -0,0 +1,196 @@
+package sentiment
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Formato da coluna date do fear_index (sempre em UTC)
+const dateLayout = "2006-01-02 15:04:05"
+
+// ImportResult resume a importação de uma fonte
+type ImportResult struct {
+	Source   string
+	From     time.Time
+	Pages    int
+	Fetched  int
+	Inserted int
+	Updated  int
+	Skipped  int
+}
+
+// Latest retorna a data do último ponto gravado em UTC para source, ou zero
+// se a fonte ainda não tem pontos. As linhas ainda no fuso local (local_time)
+// são ignoradas, já que a data delas está deslocada.
+func Latest(db *sql.DB, source string) (time.Time, error) {
+	var date sql.NullString
+	err := db.QueryRow(`SELECT MAX(date) FROM fear_index WHERE source = ? AND local_time = 0`, source).Scan(&date)
+	if err != nil || !date.Valid {
+		return time.Time{}, err
+	}
+	return parseDate(date.String)
+}
+
+// Sync importa source a partir do último ponto gravado (inclusive, para
+// atualizar o valor do dia corrente) até to. Sem pontos gravados importa
+// todo o histórico.
+func Sync(db *sql.DB, source SentimentSource, to time.Time) (ImportResult, error) {
+	from, err := Latest(db, source.Name())
+	if err != nil {
+		return ImportResult{Source: source.Name()}, fmt.Errorf("erro ao buscar o último ponto de %s: %w", source.Name(), err)
+	}
+	return Import(db, source, from, to)
+}
+
+// Import busca os pontos de source no intervalo [from, to], percorrendo as
+// páginas até a primeira anterior a from (from zero importa todo o histórico,
+// to zero vai até agora), e grava todos em uma transação no fear_index. Pontos
+// já gravados têm o valor atualizado.
+func Import(db *sql.DB, source SentimentSource, from, to time.Time) (ImportResult, error) {
+	result := ImportResult{Source: source.Name(), From: from}
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+
+	var points []Point
+	for page := 0; ; page++ {
+		body, more, err := source.Fetch(from, to, page)
+		if err != nil {
+			return result, fmt.Errorf("%s página %d: %w", source.Name(), page, err)
+		}
+		pagePoints, err := source.Parse(body)
+		if err != nil {
+			return result, fmt.Errorf("%s página %d: %w", source.Name(), page, err)
+		}
+		result.Pages++
+		result.Fetched += len(pagePoints)
+
+		reachedFrom := false
+		for _, point := range pagePoints {
+			if point.Date.Before(from) {
+				reachedFrom = true
+				continue
+			}
+			if point.Date.After(to) {
+				continue
+			}
+			points = append(points, point)
+		}
+
+		if !more || len(pagePoints) == 0 || reachedFrom {
+			break
+		}
+	}
+
+	if len(points) == 0 {
+		return result, nil
+	}
+	return result, savePoints(db, source.Name(), points, &result)
+}
+
+// Chave de um ponto no fear_index (target vazio para o índice de mercado)
+type pointKey struct {
+	target string
+	date   string
+}
+
+// savePoints grava os pontos com INSERT ... ON CONFLICT em uma única transação.
+// Os valores atuais do intervalo são lidos em uma consulta para classificar
+// cada ponto como inserido, atualizado ou sem alteração. Pontos repetidos
+// (ex: o mesmo dia em duas páginas) ficam com o último valor.
+func savePoints(db *sql.DB, source string, points []Point, result *ImportResult) error {
+	values := make(map[pointKey]float64, len(points))
+	var keys []pointKey
+	first, last := "", ""
+	for _, point := range points {
+		key := pointKey{target: point.Target, date: point.Date.UTC().Format(dateLayout)}
+		if _, exists := values[key]; !exists {
+			keys = append(keys, key)
+		}
+		values[key] = point.Value
+		if first == "" || key.date < first {
+			first = key.date
+		}
+		if key.date > last {
+			last = key.date
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+        SELECT COALESCE(target, ''), date, value, local_time
+        FROM fear_index
+        WHERE source = ? AND date BETWEEN ? AND ?;
+    `, source, first, last)
+	if err != nil {
+		return err
+	}
+	current := make(map[pointKey]float64)
+	// Linhas ainda no fuso local regravadas com o mesmo valor deixam de ser locais
+	local := make(map[pointKey]bool)
+	for rows.Next() {
+		var target string
+		var date time.Time
+		var value float64
+		var localTime bool
+		if err := rows.Scan(&target, &date, &value, &localTime); err != nil {
+			rows.Close()
+			return err
+		}
+		key := pointKey{target: target, date: date.Format(dateLayout)}
+		current[key] = value
+		local[key] = localTime
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`
+        INSERT INTO fear_index (source, target, date, value) VALUES (?, NULLIF(?, ''), ?, ?)
+        ON CONFLICT (source, COALESCE(target, ''), date) DO UPDATE SET value = excluded.value, local_time = 0;
+    `)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, key := range keys {
+		value := values[key]
+		stored, exists := current[key]
+		switch {
+		case !exists:
+			result.Inserted++
+		case stored != value || local[key]:
+			result.Updated++
+		default:
+			result.Skipped++
+			continue
+		}
+		if _, err := stmt.Exec(source, key.target, key.date, value); err != nil {
+			return fmt.Errorf("erro ao gravar %s %s %s: %w", source, key.target, key.date, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		result.Inserted, result.Updated, result.Skipped = 0, 0, 0
+		return err
+	}
+	return nil
+}
+
+// parseDate lê a coluna date do fear_index, gravada como texto ou DATETIME
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range []string{dateLayout, time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("data inválida no fear_index: %q", value)
+}
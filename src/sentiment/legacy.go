@@ -0,0 +1,103 @@
+package sentiment
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	// Fusos do -fearLegacyTZ também em máquinas sem o banco de fusos (ex: Windows)
+	_ "time/tzdata"
+)
+
+// LocalDates conta as linhas do fear_index ainda no fuso local de quem importou
+func LocalDates(db *sql.DB) (int, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM fear_index WHERE local_time = 1`).Scan(&count)
+	return count, err
+}
+
+// ConvertLocalDates converte para UTC as datas do fear_index gravadas por
+// versões anteriores no fuso local de quem importou (local_time = 1),
+// interpretando-as em loc. Quando já existe um ponto em UTC com a mesma
+// chave (ex: a fonte foi importada novamente, ou duas datas locais caem no
+// mesmo horário UTC) a linha antiga é removida.
+// Retorna quantas linhas foram convertidas e quantas removidas.
+func ConvertLocalDates(db *sql.DB, loc *time.Location) (converted, removed int, err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	type localRow struct {
+		id     int64
+		source string
+		target string
+		date   string
+	}
+	rows, err := tx.Query(`
+        SELECT id, source, COALESCE(target, ''), date
+        FROM fear_index
+        WHERE local_time = 1
+        ORDER BY id;
+    `)
+	if err != nil {
+		return 0, 0, err
+	}
+	var locals []localRow
+	for rows.Next() {
+		var row localRow
+		var date time.Time
+		if err := rows.Scan(&row.id, &row.source, &row.target, &date); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		row.date = date.Format(dateLayout)
+		locals = append(locals, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	// Libera as chaves das linhas locais: a data convertida de uma pode ser a
+	// data ainda não convertida de outra
+	if _, err := tx.Exec(`UPDATE fear_index SET date = 'local ' || date WHERE local_time = 1`); err != nil {
+		return 0, 0, err
+	}
+
+	for _, row := range locals {
+		date, err := time.ParseInLocation(dateLayout, row.date, loc)
+		if err != nil {
+			return 0, 0, fmt.Errorf("data inválida no fear_index: %q", row.date)
+		}
+		utc := date.UTC().Format(dateLayout)
+
+		var exists bool
+		err = tx.QueryRow(`
+            SELECT EXISTS (
+                SELECT 1 FROM fear_index
+                WHERE source = ? AND COALESCE(target, '') = ? AND date = ? AND local_time = 0
+            );
+        `, row.source, row.target, utc).Scan(&exists)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if exists {
+			if _, err := tx.Exec(`DELETE FROM fear_index WHERE id = ?`, row.id); err != nil {
+				return 0, 0, fmt.Errorf("erro ao remover %s %s %s: %w", row.source, row.target, row.date, err)
+			}
+			removed++
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE fear_index SET date = ?, local_time = 0 WHERE id = ?`, utc, row.id); err != nil {
+			return 0, 0, fmt.Errorf("erro ao converter %s %s %s: %w", row.source, row.target, row.date, err)
+		}
+		converted++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+	return converted, removed, nil
+}
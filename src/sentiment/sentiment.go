@@ -0,0 +1,100 @@
+package sentiment
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Point é um valor de um índice de sentimento publicado em Date (UTC). Target
+// é o símbolo da crypto (ex: "BTC") nos índices por crypto e vazio nos
+// índices de mercado.
+type Point struct {
+	Target string
+	Date   time.Time
+	Value  float64
+}
+
+// Fonte padrão das séries importadas de arquivos locais (ImportFile)
+const LocalSource = "local"
+
+// SentimentSource é um índice de sentimento de mercado (ex: Fear & Greed)
+// importado para a tabela fear_index e emitido como uma coluna do dataset.
+// Paginação, filtragem do intervalo e gravação ficam no importador comum.
+type SentimentSource interface {
+	// Name é o valor gravado na coluna source do fear_index
+	Name() string
+	// Column é o nome da coluna do índice no dataset (ex: "fear_coinmarketcap")
+	Column() string
+	// Fetch busca a página page (a partir de 0) com os pontos do intervalo
+	// [from, to]. Um from zero pede todo o histórico. Retorna o corpo da
+	// resposta e se ainda há páginas.
+	Fetch(from, to time.Time, page int) (body []byte, more bool, err error)
+	// Parse converte o corpo retornado por Fetch em pontos
+	Parse(body []byte) ([]Point, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]SentimentSource)
+)
+
+// Register adiciona uma fonte ao registro. Deve ser chamado em init().
+func Register(source SentimentSource) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[source.Name()]; exists {
+		panic(fmt.Sprintf("fonte de sentimento já registrada: %s", source.Name()))
+	}
+	for _, other := range registry {
+		if other.Column() == source.Column() {
+			panic(fmt.Sprintf("coluna de sentimento já registrada: %s", source.Column()))
+		}
+	}
+	registry[source.Name()] = source
+}
+
+// Get retorna a fonte registrada com o nome informado
+func Get(name string) (SentimentSource, error) {
+	registryMu.RLock()
+	source, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("fonte de sentimento desconhecida: %s (disponíveis: %s)", name, strings.Join(Names(), ", "))
+	}
+	return source, nil
+}
+
+// Sources lista as fontes registradas na ordem das colunas do dataset
+func Sources() []SentimentSource {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	sources := make([]SentimentSource, 0, len(registry))
+	for _, source := range registry {
+		sources = append(sources, source)
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Column() < sources[j].Column() })
+	return sources
+}
+
+// Names lista os nomes das fontes registradas em ordem alfabética
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Cliente HTTP usado pelas fontes
+var httpClient = &http.Client{Timeout: 10 * time.Second}
@@ -0,0 +1,58 @@
+package strategy
+
+import (
+	"app/src/models"
+	"fmt"
+)
+
+func init() {
+	Register("percentChange", newPercentChange)
+}
+
+// percentChange compra quando o fechamento cai threshold% em relação ao candle
+// anterior e vende quando sobe threshold%. É a regra original do traderBot.
+type percentChange struct {
+	threshold float64
+	quantity  float64
+	prevClose map[string]float64
+}
+
+func newPercentChange(params Params) (Strategy, error) {
+	threshold, err := params.Float("threshold", 0.5)
+	if err != nil {
+		return nil, err
+	}
+	quantity, err := params.Float("quantity", 0.001)
+	if err != nil {
+		return nil, err
+	}
+
+	return &percentChange{
+		threshold: threshold,
+		quantity:  quantity,
+		prevClose: make(map[string]float64),
+	}, nil
+}
+
+func (s *percentChange) Name() string {
+	return "percentChange"
+}
+
+func (s *percentChange) OnCandle(candle models.Candle) Signal {
+	prevClose, ok := s.prevClose[candle.Symbol]
+	s.prevClose[candle.Symbol] = candle.Close
+	if !ok || prevClose == 0 {
+		return Hold("sem candle anterior")
+	}
+
+	change := (candle.Close - prevClose) / prevClose * 100
+	reason := fmt.Sprintf("preço anterior: %.2f, atual: %.2f, variação: %.2f%%", prevClose, candle.Close, change)
+
+	if change <= -s.threshold {
+		return Signal{Action: ActionBuy, Quantity: s.quantity, Reason: reason}
+	} else if change >= s.threshold {
+		return Signal{Action: ActionSell, Quantity: s.quantity, Reason: reason}
+	}
+
+	return Hold(reason)
+}
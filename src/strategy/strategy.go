@@ -0,0 +1,128 @@
+package strategy
+
+import (
+	"app/src/models"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Ação sugerida pela estratégia para um candle
+type Action string
+
+const (
+	ActionHold Action = "HOLD"
+	ActionBuy  Action = "BUY"
+	ActionSell Action = "SELL"
+)
+
+// Signal é a resposta da estratégia a cada candle fechado
+type Signal struct {
+	Action   Action
+	Quantity float64
+	Reason   string
+}
+
+// Hold é o sinal padrão quando não há nada a fazer
+func Hold(reason string) Signal {
+	return Signal{Action: ActionHold, Reason: reason}
+}
+
+// Strategy recebe os candles fechados em ordem cronológica (de um ou mais
+// símbolos) e decide se compra, vende ou mantém a posição.
+type Strategy interface {
+	Name() string
+	OnCandle(candle models.Candle) Signal
+}
+
+// Params são os parâmetros de configuração de uma estratégia (chave=valor)
+type Params map[string]string
+
+// Factory cria uma nova instância da estratégia a partir dos parâmetros
+type Factory func(params Params) (Strategy, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register adiciona uma estratégia ao registro. Deve ser chamado em init().
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("estratégia já registrada: %s", name))
+	}
+	registry[name] = factory
+}
+
+// New cria a estratégia registrada com o nome informado
+func New(name string, params Params) (Strategy, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("estratégia desconhecida: %s (disponíveis: %s)", name, strings.Join(Names(), ", "))
+	}
+	return factory(params)
+}
+
+// Names lista as estratégias registradas em ordem alfabética
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseParams converte "chave=valor,chave2=valor2" em Params
+func ParseParams(raw string) (Params, error) {
+	params := make(Params)
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("parâmetro inválido %q, use chave=valor", pair)
+		}
+		params[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return params, nil
+}
+
+// Float lê um parâmetro numérico, usando defaultValue se ausente
+func (p Params) Float(key string, defaultValue float64) (float64, error) {
+	raw, ok := p[key]
+	if !ok || raw == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parâmetro %s inválido: %w", key, err)
+	}
+	return value, nil
+}
+
+// Int lê um parâmetro inteiro, usando defaultValue se ausente
+func (p Params) Int(key string, defaultValue int) (int, error) {
+	raw, ok := p[key]
+	if !ok || raw == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("parâmetro %s inválido: %w", key, err)
+	}
+	return value, nil
+}
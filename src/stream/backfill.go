@@ -0,0 +1,84 @@
+package stream
+
+import (
+	"app/src/binanceapi"
+	"app/src/models"
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Klines por requisição do backfill (máximo da API)
+const backfillLimit = binanceapi.MaxKlinesLimit
+
+// backfill entrega os candles do símbolo fechados desde o último entregue,
+// buscados na API REST. Antes do primeiro candle não há o que recuperar.
+func (s *KlineStream) backfill(ctx context.Context, symbol string) error {
+	s.mu.Lock()
+	last := s.last[symbol]
+	s.mu.Unlock()
+	if last == 0 {
+		return nil
+	}
+
+	recovered := 0
+	defer func() {
+		if recovered > 0 {
+			log.Printf("🩹 %s: %d candles recuperados via REST após a reconexão", symbol, recovered)
+		}
+	}()
+
+	startTime := last + s.step
+	for {
+		candles, err := s.fetchKlines(ctx, symbol, startTime)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UnixMilli()
+		for _, candle := range candles {
+			// O candle em formação chega pelo stream quando fechar
+			if candle.CloseTime >= now {
+				return nil
+			}
+			delivered, err := s.publish(ctx, candle)
+			if err != nil {
+				return err
+			}
+			if delivered {
+				recovered++
+			}
+			startTime = candle.OpenTime + s.step
+		}
+		if len(candles) < backfillLimit {
+			return nil
+		}
+	}
+}
+
+// fetchKlines busca até backfillLimit klines do símbolo a partir de startTime
+func (s *KlineStream) fetchKlines(ctx context.Context, symbol string, startTime int64) ([]models.Candle, error) {
+	klines, err := s.rest.Klines(ctx, symbol, s.config.Interval, startTime, 0, backfillLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	candles := make([]models.Candle, 0, len(klines))
+	for _, kline := range klines {
+		payload := klinePayload{
+			OpenTime:  kline.OpenTime,
+			CloseTime: kline.CloseTime,
+			Open:      kline.Open,
+			High:      kline.High,
+			Low:       kline.Low,
+			Close:     kline.Close,
+			Volume:    kline.Volume,
+		}
+		candle, err := payload.candle(symbol)
+		if err != nil {
+			return nil, fmt.Errorf("kline inválido: %w", err)
+		}
+		candles = append(candles, candle)
+	}
+	return candles, nil
+}
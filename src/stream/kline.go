@@ -0,0 +1,312 @@
+package stream
+
+import (
+	"app/src/binanceapi"
+	"app/src/constants"
+	"app/src/models"
+	"app/src/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Config do stream de klines
+type Config struct {
+	// URL base do WebSocket (wss://stream.binance.com:9443 ou um stub local).
+	// A conexão usa o endpoint combinado <URL>/stream.
+	URL string
+	// URL base da API REST usada no backfill após uma reconexão
+	RESTURL string
+	// Pares acompanhados (ex: BTCUSDT) e intervalo dos klines
+	Symbols  []string
+	Interval string
+	// Espera antes da primeira tentativa de reconexão, dobrada a cada falha até MaxReconnectDelay
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+	// Sem nenhuma mensagem nesse período a conexão é considerada perdida
+	ReadTimeout time.Duration
+}
+
+// DefaultConfig usa os endpoints da Binance, que podem apontar para servidores
+// locais via BINANCE_STREAM_BASE_URL e BINANCE_API_BASE_URL
+func DefaultConfig() Config {
+	config := Config{
+		URL:               constants.BINANCE_STREAM_BASE_URL,
+		RESTURL:           binanceapi.DefaultConfig().BaseURL,
+		Interval:          "1m",
+		ReconnectDelay:    time.Second,
+		MaxReconnectDelay: time.Minute,
+		ReadTimeout:       time.Minute,
+	}
+	if url := os.Getenv("BINANCE_STREAM_BASE_URL"); url != "" {
+		config.URL = strings.TrimRight(url, "/")
+	}
+	return config
+}
+
+// KlineStream acompanha os streams <symbol>@kline_<interval> da Binance e
+// entrega cada candle fechado, uma única vez e em ordem por símbolo, a todos os
+// inscritos. Após uma queda reconecta, refaz a inscrição e busca via REST os
+// candles fechados enquanto esteve desconectado.
+type KlineStream struct {
+	config Config
+	step   int64
+	dialer *websocket.Dialer
+	rest   *binanceapi.Client
+
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	// Último OpenTime entregue por símbolo
+	last map[string]int64
+}
+
+// New valida a configuração e cria o stream. A conexão só é aberta em Run.
+func New(config Config) (*KlineStream, error) {
+	duration, err := utils.IntervalDuration(config.Interval)
+	if err != nil {
+		return nil, err
+	}
+	if len(config.Symbols) == 0 {
+		return nil, fmt.Errorf("nenhum símbolo informado para o stream")
+	}
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = time.Second
+	}
+	if config.MaxReconnectDelay < config.ReconnectDelay {
+		config.MaxReconnectDelay = config.ReconnectDelay
+	}
+	symbols := make([]string, len(config.Symbols))
+	for i, symbol := range config.Symbols {
+		symbols[i] = strings.ToUpper(symbol)
+	}
+	config.Symbols = symbols
+
+	restConfig := binanceapi.DefaultConfig()
+	restConfig.BaseURL = config.RESTURL
+	return &KlineStream{
+		config:      config,
+		step:        duration.Milliseconds(),
+		dialer:      &websocket.Dialer{HandshakeTimeout: 10 * time.Second},
+		rest:        binanceapi.New(restConfig),
+		subscribers: make(map[*subscriber]struct{}),
+		last:        make(map[string]int64),
+	}, nil
+}
+
+// Inscrito do stream. done é fechado quando a inscrição é cancelada.
+type subscriber struct {
+	ch   chan models.Candle
+	done chan struct{}
+}
+
+// Subscribe registra um inscrito e retorna o canal dos candles fechados e a
+// função que cancela a inscrição. O canal é fechado quando Run termina e deixa
+// de receber candles após o cancelamento. Um inscrito lento atrasa a entrega
+// aos demais, então use buffer suficiente.
+func (s *KlineStream) Subscribe(buffer int) (<-chan models.Candle, func()) {
+	sub := &subscriber{ch: make(chan models.Candle, buffer), done: make(chan struct{})}
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subscribers, sub)
+			s.mu.Unlock()
+			close(sub.done)
+		})
+	}
+}
+
+// Run mantém a conexão até ctx ser cancelado, reconectando com espera
+// crescente. Retorna o erro do contexto e fecha os canais dos inscritos.
+func (s *KlineStream) Run(ctx context.Context) error {
+	defer s.closeSubscribers()
+
+	delay := s.config.ReconnectDelay
+	for {
+		connected, err := s.session(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if connected {
+			delay = s.config.ReconnectDelay
+		}
+		log.Printf("🔌 Stream de klines desconectado: %v. Reconectando em %s...", err, delay)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		if !connected {
+			delay = min(delay*2, s.config.MaxReconnectDelay)
+		}
+	}
+}
+
+// Pedido de inscrição nos streams, reenviado a cada conexão
+type subscribeRequest struct {
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+	ID     int      `json:"id"`
+}
+
+// Mensagem do endpoint combinado: {"stream": "...", "data": {...}}. As
+// respostas aos pedidos de inscrição vêm sem stream.
+type combinedMessage struct {
+	Stream string     `json:"stream"`
+	Data   klineEvent `json:"data"`
+}
+
+type klineEvent struct {
+	Event  string       `json:"e"`
+	Symbol string       `json:"s"`
+	Kline  klinePayload `json:"k"`
+}
+
+type klinePayload struct {
+	OpenTime  int64  `json:"t"`
+	CloseTime int64  `json:"T"`
+	Open      string `json:"o"`
+	High      string `json:"h"`
+	Low       string `json:"l"`
+	Close     string `json:"c"`
+	Volume    string `json:"v"`
+	Closed    bool   `json:"x"`
+}
+
+// session abre uma conexão, inscreve os streams, faz o backfill e lê as
+// mensagens até a conexão cair. connected indica se a inscrição e o backfill
+// foram feitos (senão a espera da reconexão continua crescendo).
+func (s *KlineStream) session(ctx context.Context) (connected bool, err error) {
+	conn, _, err := s.dialer.DialContext(ctx, s.config.URL+"/stream", nil)
+	if err != nil {
+		return false, fmt.Errorf("erro ao conectar em %s: %w", s.config.URL, err)
+	}
+	// Fecha a conexão quando o contexto é cancelado para desbloquear a leitura
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	defer conn.Close()
+
+	streams := make([]string, len(s.config.Symbols))
+	for i, symbol := range s.config.Symbols {
+		streams[i] = strings.ToLower(symbol) + "@kline_" + s.config.Interval
+	}
+	if err := conn.WriteJSON(subscribeRequest{Method: "SUBSCRIBE", Params: streams, ID: 1}); err != nil {
+		return false, fmt.Errorf("erro ao inscrever os streams: %w", err)
+	}
+	log.Printf("📡 Stream de klines conectado: %s", strings.Join(streams, ", "))
+
+	// Candles fechados durante a queda. As mensagens recebidas enquanto isso
+	// ficam no buffer da conexão e as repetidas são descartadas em publish. Se
+	// o backfill falhar a sessão é encerrada antes de entregar candles mais
+	// novos, que avançariam o último entregue e deixariam a lacuna para trás;
+	// a reconexão tenta o backfill novamente.
+	for _, symbol := range s.config.Symbols {
+		if err := s.backfill(ctx, symbol); err != nil {
+			return false, fmt.Errorf("erro no backfill de %s: %w", symbol, err)
+		}
+	}
+
+	for {
+		if s.config.ReadTimeout > 0 {
+			conn.SetReadDeadline(time.Now().Add(s.config.ReadTimeout))
+		}
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return true, err
+		}
+
+		var message combinedMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			log.Printf("⚠️ Mensagem inválida no stream: %v", err)
+			continue
+		}
+		if message.Stream == "" || message.Data.Event != "kline" || !message.Data.Kline.Closed {
+			continue
+		}
+		candle, err := message.Data.Kline.candle(message.Data.Symbol)
+		if err != nil {
+			log.Printf("⚠️ Kline inválido no stream: %v", err)
+			continue
+		}
+		if _, err := s.publish(ctx, candle); err != nil {
+			return true, err
+		}
+	}
+}
+
+func (k klinePayload) candle(symbol string) (models.Candle, error) {
+	candle := models.Candle{Symbol: symbol, OpenTime: k.OpenTime, CloseTime: k.CloseTime}
+	var errs []error
+	for _, field := range []struct {
+		value string
+		dest  *float64
+	}{
+		{k.Open, &candle.Open},
+		{k.High, &candle.High},
+		{k.Low, &candle.Low},
+		{k.Close, &candle.Close},
+		{k.Volume, &candle.Volume},
+	} {
+		value, err := strconv.ParseFloat(field.value, 64)
+		errs = append(errs, err)
+		*field.dest = value
+	}
+	return candle, errors.Join(errs...)
+}
+
+// publish entrega o candle aos inscritos se for posterior ao último entregue
+// do símbolo e indica se ele foi entregue
+func (s *KlineStream) publish(ctx context.Context, candle models.Candle) (bool, error) {
+	s.mu.Lock()
+	if candle.OpenTime <= s.last[candle.Symbol] {
+		s.mu.Unlock()
+		return false, nil
+	}
+	s.last[candle.Symbol] = candle.OpenTime
+	subscribers := make([]*subscriber, 0, len(s.subscribers))
+	for sub := range s.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	s.mu.Unlock()
+
+	for _, sub := range subscribers {
+		select {
+		case sub.ch <- candle:
+		case <-sub.done:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+	return true, nil
+}
+
+// closeSubscribers fecha os canais ao fim do Run, quando não há mais envios
+func (s *KlineStream) closeSubscribers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers {
+		delete(s.subscribers, sub)
+		close(sub.ch)
+	}
+}
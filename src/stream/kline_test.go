@@ -0,0 +1,250 @@
+package stream
+
+import (
+	"app/src/binanceapi"
+	"app/src/models"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const testStep = int64(60000)
+
+// wsStub é um servidor WebSocket local que roda um roteiro por conexão e
+// guarda os pedidos de inscrição recebidos
+type wsStub struct {
+	t       *testing.T
+	scripts []func(conn *websocket.Conn)
+
+	mu         sync.Mutex
+	conns      int
+	subscribes []subscribeRequest
+}
+
+func (w *wsStub) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/stream" {
+		http.NotFound(rw, r)
+		return
+	}
+	upgrader := websocket.Upgrader{}
+	conn, err := upgrader.Upgrade(rw, r, nil)
+	if err != nil {
+		w.t.Errorf("upgrade: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	var request subscribeRequest
+	if err := conn.ReadJSON(&request); err != nil {
+		return
+	}
+	w.mu.Lock()
+	index := w.conns
+	w.conns++
+	w.subscribes = append(w.subscribes, request)
+	w.mu.Unlock()
+	conn.WriteJSON(map[string]any{"result": nil, "id": request.ID})
+
+	if index < len(w.scripts) {
+		w.scripts[index](conn)
+		return
+	}
+	// Sem roteiro: mantém a conexão aberta até o cliente sair
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// sendKline envia um evento de kline do endpoint combinado
+func sendKline(t *testing.T, conn *websocket.Conn, openTime int64, closed bool) {
+	t.Helper()
+	message := map[string]any{
+		"stream": "btcusdt@kline_1m",
+		"data": map[string]any{
+			"e": "kline",
+			"s": "BTCUSDT",
+			"k": map[string]any{
+				"t": openTime,
+				"T": openTime + testStep - 1,
+				"o": "1", "h": "2", "l": "0.5", "c": strconv.FormatInt(openTime/testStep, 10), "v": "10",
+				"x": closed,
+			},
+		},
+	}
+	if err := conn.WriteJSON(message); err != nil {
+		t.Errorf("write: %v", err)
+	}
+}
+
+// restStub responde /api/v3/klines com candles de startTime até lastOpen.
+// As primeiras failures requisições recebem 400.
+type restStub struct {
+	mu       sync.Mutex
+	lastOpen int64
+	failures int
+	requests []int64
+}
+
+func (r *restStub) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	startTime, _ := strconv.ParseInt(req.URL.Query().Get("startTime"), 10, 64)
+	r.mu.Lock()
+	r.requests = append(r.requests, startTime)
+	fail := r.failures > 0
+	if fail {
+		r.failures--
+	}
+	lastOpen := r.lastOpen
+	r.mu.Unlock()
+
+	if fail {
+		http.Error(rw, `{"code":-1,"msg":"falha simulada"}`, http.StatusBadRequest)
+		return
+	}
+	var rows []string
+	for openTime := startTime; openTime <= lastOpen; openTime += testStep {
+		rows = append(rows, fmt.Sprintf(`[%d,"1","2","0.5","%d","10",%d,"100",5,"1","1","0"]`,
+			openTime, openTime/testStep, openTime+testStep-1))
+	}
+	rw.Write([]byte("[" + strings.Join(rows, ",") + "]"))
+}
+
+func newTestStream(t *testing.T, ws *wsStub, rest *restStub) *KlineStream {
+	t.Helper()
+	wsServer := httptest.NewServer(ws)
+	t.Cleanup(wsServer.Close)
+	restServer := httptest.NewServer(rest)
+	t.Cleanup(restServer.Close)
+
+	config := DefaultConfig()
+	config.URL = "ws" + strings.TrimPrefix(wsServer.URL, "http")
+	config.RESTURL = restServer.URL
+	config.Symbols = []string{"btcusdt"}
+	config.ReconnectDelay = 10 * time.Millisecond
+	config.MaxReconnectDelay = 50 * time.Millisecond
+	config.ReadTimeout = 5 * time.Second
+	s, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sem esperas longas entre as tentativas do REST
+	restConfig := binanceapi.DefaultConfig()
+	restConfig.BaseURL = restServer.URL
+	restConfig.MaxRetries = 0
+	s.rest = binanceapi.New(restConfig)
+	return s
+}
+
+// collect lê count candles do canal ou falha após o timeout
+func collect(t *testing.T, candles <-chan models.Candle, count int) []int64 {
+	t.Helper()
+	var openTimes []int64
+	timeout := time.After(5 * time.Second)
+	for len(openTimes) < count {
+		select {
+		case candle, ok := <-candles:
+			if !ok {
+				t.Fatalf("canal fechado após %v", openTimes)
+			}
+			openTimes = append(openTimes, candle.OpenTime)
+		case <-timeout:
+			t.Fatalf("esperava %d candles, recebeu %v", count, openTimes)
+		}
+	}
+	return openTimes
+}
+
+func TestKlineStreamReconnectAndBackfill(t *testing.T) {
+	base := time.Now().Add(-time.Hour).Truncate(time.Minute).UnixMilli()
+	at := func(i int) int64 { return base + int64(i)*testStep }
+
+	// Os candles 2 e 3 fecham enquanto o stream está desconectado
+	rest := &restStub{lastOpen: at(3), failures: 1}
+	ws := &wsStub{t: t}
+	ws.scripts = []func(conn *websocket.Conn){
+		func(conn *websocket.Conn) {
+			sendKline(t, conn, at(0), false) // em formação: ignorado
+			sendKline(t, conn, at(0), true)
+			sendKline(t, conn, at(0), true) // repetido
+			sendKline(t, conn, at(1), true)
+			time.Sleep(100 * time.Millisecond)
+		},
+		// O primeiro backfill falha: a sessão é encerrada sem ler mensagens
+		func(conn *websocket.Conn) {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		},
+		func(conn *websocket.Conn) {
+			sendKline(t, conn, at(3), true) // já recuperado pelo backfill
+			sendKline(t, conn, at(4), false)
+			sendKline(t, conn, at(4), true)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		},
+	}
+
+	s := newTestStream(t, ws, rest)
+	candles, cancel := s.Subscribe(16)
+	defer cancel()
+
+	ctx, stop := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	got := collect(t, candles, 5)
+	want := []int64{at(0), at(1), at(2), at(3), at(4)}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("candles = %v, esperado %v", got, want)
+		}
+	}
+
+	// Nada além dos 5 candles (sem repetidos nem em formação)
+	select {
+	case candle := <-candles:
+		t.Fatalf("candle inesperado: %+v", candle)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	stop()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("Run retornou %v", err)
+	}
+	if _, ok := <-candles; ok {
+		t.Fatal("canal não foi fechado ao fim do Run")
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if len(ws.subscribes) < 3 {
+		t.Fatalf("esperava 3 inscrições, recebeu %d", len(ws.subscribes))
+	}
+	for i, request := range ws.subscribes {
+		if request.Method != "SUBSCRIBE" || len(request.Params) != 1 || request.Params[0] != "btcusdt@kline_1m" {
+			t.Fatalf("inscrição %d inválida: %+v", i, request)
+		}
+	}
+
+	rest.mu.Lock()
+	defer rest.mu.Unlock()
+	// Backfill que falhou e o repetido na reconexão, ambos a partir do candle 2
+	if len(rest.requests) != 2 || rest.requests[0] != at(2) || rest.requests[1] != at(2) {
+		t.Fatalf("requisições REST = %v, esperado duas a partir de %d", rest.requests, at(2))
+	}
+}
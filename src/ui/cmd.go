@@ -1,15 +1,23 @@
 package ui
 
 import (
+	"app/src/scripts/backtest"
 	"app/src/scripts/disableCryptos"
 	"app/src/scripts/generateDataset"
 	"app/src/scripts/generateModels"
 	"app/src/scripts/getBinanceData"
 	"app/src/scripts/getDailyPrices"
 	"app/src/scripts/getFearIndex"
+	"app/src/scripts/ledgerReport"
+	"app/src/scripts/repairGaps"
+	"app/src/scripts/syncSymbols"
+	"app/src/scripts/traderBot"
+	"app/src/strategy"
+	"app/src/utils"
 	"bufio"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -32,22 +40,38 @@ func MainCMD() {
 			fmt.Println("\n🔍 Executando GetFearCoinmarketcap...")
 			getFearIndex.GetFearCoinmarketcap(searchForAll)
 		case "2":
+			fmt.Print("Buscar de todo periodo? (s/n): ")
+			scanner.Scan()
+			input := strings.TrimSpace(scanner.Text())
+			searchForAll := input == "s" || input == "S"
 			fmt.Println("\n🔍 Executando GetFearAlternativeMe...")
-			getFearIndex.GetFearAlternativeMe()
+			getFearIndex.GetFearAlternativeMe(searchForAll)
 		case "3":
+			config := getDailyPrices.DefaultConfig()
+			config.Interval = getInterval(scanner)
+			fmt.Print("Exportar também em CSV? (s/n): ")
+			scanner.Scan()
+			input := strings.TrimSpace(scanner.Text())
+			config.CSV = input == "s" || input == "S"
 			fmt.Println("\n🔍 Executando GetBinanceCurrentDayCryptos...")
-			getDailyPrices.Main()
+			getDailyPrices.Main(config)
 		case "4":
 			fmt.Print("Buscar todas as criptomoedas? (s/n): ")
 			scanner.Scan()
 			input := strings.TrimSpace(scanner.Text())
 			isAllCryptosEnabled := input == "s" || input == "S"
+			interval := getInterval(scanner)
+			fmt.Print("Baixar novamente todos os CSVs anteriores ao manifesto? (s/n): ")
+			scanner.Scan()
+			input = strings.TrimSpace(scanner.Text())
+			verifyExisting := input == "s" || input == "S"
 			fmt.Println("\n🔍 Executando DownloadBinanceCryptoData...")
-			getBinanceData.Main(isAllCryptosEnabled)
+			getBinanceData.Main(isAllCryptosEnabled, interval, verifyExisting)
 		case "5":
 			fmt.Println("\n🔍 Executando DisableCryptos...")
 			minDate, maxDate := getDateRange()
-			disableCryptos.Main(minDate, maxDate)
+			interval := getInterval(scanner)
+			disableCryptos.Main(minDate, maxDate, interval)
 		case "6":
 			fmt.Println("\n🔍 Executando GenerateDataset...")
 			startDateStr, endDateStr := getDateRange()
@@ -65,10 +89,68 @@ func MainCMD() {
 			scanner.Scan()
 			input := strings.TrimSpace(scanner.Text())
 			clearFiles := input == "s" || input == "S"
-			generateDataset.Main(startDate, endDate, clearFiles)
+			config := generateDataset.DefaultConfig()
+			config.Start = startDate
+			config.End = endDate
+			config.ClearFiles = clearFiles
+			config.Interval = getInterval(scanner)
+			generateDataset.Main(config)
 		case "7":
 			fmt.Println("\n🔍 Executando GenerateModels...")
 			generateModels.Main()
+		case "8":
+			fmt.Print("Executar em modo paper trading (ordens simuladas)? (s/n): ")
+			scanner.Scan()
+			input := strings.TrimSpace(scanner.Text())
+			paper := input == "s" || input == "S"
+			paperBalance := 0.0
+			if paper {
+				paperBalance = getFloat(scanner, "Saldo inicial em USDT", 1000)
+			}
+			config := traderBot.DefaultConfig()
+			fmt.Printf("Estratégia (%s) [%s]: ", strings.Join(strategy.Names(), ", "), config.Strategy)
+			scanner.Scan()
+			if input := strings.TrimSpace(scanner.Text()); input != "" {
+				config.Strategy = input
+			}
+			config.Paper = paper
+			config.PaperBalance = paperBalance
+			fmt.Println("\n🤖 Executando TraderBot...")
+			traderBot.Main(config)
+		case "9":
+			fmt.Println("\n🔍 Executando Backtest...")
+			startDateStr, endDateStr := getDateRange()
+			config := backtest.DefaultConfig()
+			config.Start, _ = time.Parse("2006-01-02", startDateStr)
+			config.End, _ = time.Parse("2006-01-02", endDateStr)
+			fmt.Printf("Estratégia (%s) [%s]: ", strings.Join(strategy.Names(), ", "), config.Strategy)
+			scanner.Scan()
+			if input := strings.TrimSpace(scanner.Text()); input != "" {
+				config.Strategy = input
+			}
+			fmt.Printf("Pares separados por vírgula [%s]: ", strings.Join(config.Symbols, ","))
+			scanner.Scan()
+			if input := strings.TrimSpace(scanner.Text()); input != "" {
+				config.Symbols = strings.Split(strings.ToUpper(strings.ReplaceAll(input, " ", "")), ",")
+			}
+			config.InitialBalance = getFloat(scanner, "Saldo inicial em USDT", config.InitialBalance)
+			config.FeeRate = getFloat(scanner, "Taxa por trade", config.FeeRate)
+			config.Slippage = getFloat(scanner, "Slippage por trade", config.Slippage)
+			backtest.Main(config)
+		case "10":
+			fmt.Println("\n🔍 Executando SyncSymbols...")
+			syncSymbols.Main()
+		case "11":
+			fmt.Println("\n🩹 Executando RepairGaps...")
+			startDateStr, endDateStr := getDateRange()
+			config := repairGaps.DefaultConfig()
+			config.Start, _ = time.Parse("2006-01-02", startDateStr)
+			config.End, _ = time.Parse("2006-01-02", endDateStr)
+			config.Interval = getInterval(scanner)
+			repairGaps.Main(config)
+		case "12":
+			fmt.Println("\n📒 Executando LedgerReport...")
+			ledgerReport.Main()
 		default:
 			fmt.Println("\n❌ Opção inválida! Por favor, escolha uma opção válida.")
 		}
@@ -88,6 +170,11 @@ func showMenu() {
 	fmt.Println("5. 🔄 DisableCryptos")
 	fmt.Println("6. 📊 GenerateDataset")
 	fmt.Println("7. 📊 GenerateModels")
+	fmt.Println("8. 🤖 TraderBot")
+	fmt.Println("9. 🧪 Backtest")
+	fmt.Println("10. 🔄 SyncSymbols")
+	fmt.Println("11. 🩹 RepairGaps")
+	fmt.Println("12. 📒 LedgerReport")
 	fmt.Println(strings.Repeat("=", 40))
 	fmt.Print("Escolha uma opção: ")
 }
@@ -141,6 +228,37 @@ func getDateRange() (string, string) {
 	return minDate, maxDate
 }
 
+// Lê o intervalo dos klines, usando 1m quando a entrada for vazia ou inválida
+func getInterval(scanner *bufio.Scanner) string {
+	fmt.Printf("Intervalo dos klines (%s) [1m]: ", strings.Join(utils.KlineIntervals, ", "))
+	scanner.Scan()
+	input := strings.TrimSpace(scanner.Text())
+	if input == "" {
+		return "1m"
+	}
+	if !utils.IsValidInterval(input) {
+		fmt.Println("❌ Intervalo inválido, usando 1m")
+		return "1m"
+	}
+	return input
+}
+
+// Lê um número do usuário, usando defaultValue quando a entrada for vazia ou inválida
+func getFloat(scanner *bufio.Scanner, label string, defaultValue float64) float64 {
+	fmt.Printf("%s [%g]: ", label, defaultValue)
+	scanner.Scan()
+	input := strings.TrimSpace(scanner.Text())
+	if input == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(input, 64)
+	if err != nil {
+		fmt.Printf("❌ Valor inválido, usando %g\n", defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
 func isValidDate(dateStr string) bool {
 	_, err := time.Parse("2006-01-02", dateStr)
 	return err == nil
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Intervalos de kline suportados pelos scripts, em ordem crescente
+var KlineIntervals = []string{"1m", "5m", "15m", "1h", "4h", "1d"}
+
+var klineIntervalDurations = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// IntervalDuration retorna a duração de um candle do intervalo informado
+func IntervalDuration(interval string) (time.Duration, error) {
+	duration, ok := klineIntervalDurations[interval]
+	if !ok {
+		return 0, fmt.Errorf("intervalo inválido: %s (use %s)", interval, strings.Join(KlineIntervals, ", "))
+	}
+	return duration, nil
+}
+
+// IsValidInterval indica se o intervalo é suportado
+func IsValidInterval(interval string) bool {
+	_, ok := klineIntervalDurations[interval]
+	return ok
+}
+
+// RowsPerDay é a quantidade de candles de um dia completo no intervalo
+func RowsPerDay(interval string) int {
+	duration, err := IntervalDuration(interval)
+	if err != nil {
+		return 0
+	}
+	return int(24 * time.Hour / duration)
+}
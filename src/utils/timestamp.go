@@ -0,0 +1,10 @@
+package utils
+
+// NormalizeTimestampMs converte timestamps em microssegundos para milissegundos.
+// Os arquivos de spot do data.binance.vision usam microssegundos desde 2025.
+func NormalizeTimestampMs(ts int64) int64 {
+	if ts > 1e14 {
+		return ts / 1000
+	}
+	return ts
+}